@@ -0,0 +1,27 @@
+package termcols
+
+// Adaptive pairs two SGR attributes of which one is meant to be used on
+// terminals with a light background and the other one on terminals with a
+// dark background. The choice between the two is deferred until SgrAttr is
+// called, so the same code reads well regardless of the terminal theme.
+type Adaptive struct {
+	Light SgrAttr
+	Dark  SgrAttr
+}
+
+// Resolve returns the Dark attribute when dark is true and the Light
+// attribute otherwise.
+func (a Adaptive) Resolve(dark bool) SgrAttr {
+	if dark {
+		return a.Dark
+	}
+	return a.Light
+}
+
+// SgrAttr returns the attribute matching the terminal background as reported
+// by [IsDarkBackground] at the time of the call. The result is a plain SGR
+// control sequence that can be passed along with other attributes to
+// Colorize, Style, Combine or a Renderer, or written out as it is.
+func (a Adaptive) SgrAttr() SgrAttr {
+	return a.Resolve(IsDarkBackground())
+}
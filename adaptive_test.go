@@ -0,0 +1,60 @@
+package termcols
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAdaptiveResolve(t *testing.T) {
+	cases := []struct {
+		name   string
+		a      Adaptive
+		dark   bool
+		expOut SgrAttr
+	}{
+		{"dark", Adaptive{BlackFg, WhiteFg}, true, WhiteFg},
+		{"light", Adaptive{BlackFg, WhiteFg}, false, BlackFg},
+		{"rgb-dark", Adaptive{Rgb8(FG, 18), Rgb24(FG, 250, 250, 0)}, true, Rgb24(FG, 250, 250, 0)},
+		{"empty-light", Adaptive{Dark: Bold}, false, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if out := c.a.Resolve(c.dark); out != c.expOut {
+				t.Errorf("Have: %q, want: %q", out, c.expOut)
+			}
+		})
+	}
+}
+
+func TestAdaptiveSgrAttr(t *testing.T) {
+	a := Adaptive{Light: BlueFg, Dark: YellowFg}
+	for _, dark := range []bool{true, false, true} {
+		SetDarkBackground(dark)
+		if out, want := a.SgrAttr(), a.Resolve(dark); out != want {
+			t.Errorf("Have: %q, want: %q", out, want)
+		}
+		if out := IsDarkBackground(); out != dark {
+			t.Errorf("Have: %t, want: %t", out, dark)
+		}
+	}
+}
+
+// Test that the attribute is a control sequence that can be written out as it
+// is and read back by the functions measuring and naming attributes.
+func TestAdaptiveRaw(t *testing.T) {
+	SetDarkBackground(true)
+	a := Adaptive{Light: BlueFg, Dark: Rgb8(FG, 226)}.SgrAttr()
+	raw := fmt.Sprint(a, "x")
+	if want := string(Rgb8(FG, 226)) + "x"; raw != want {
+		t.Errorf("Have: %q, want: %q", raw, want)
+	}
+	if out := Width(raw); out != 1 {
+		t.Errorf("Have: %d, want: 1", out)
+	}
+	if out, ok := Name(a); !ok || out != "rgb8=fg:226" {
+		t.Errorf("Have: %q %t, want: %q", out, ok, "rgb8=fg:226")
+	}
+	if out := Truncate(raw+"yz", 1, ""); out != string(a)+"x"+string(Reset) {
+		t.Errorf("Have: %q, want: %q", out, string(a)+"x"+string(Reset))
+	}
+}
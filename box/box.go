@@ -0,0 +1,178 @@
+/*
+Package box draws borders around text on the terminal to build banners,
+panels and warnings. Text can span multiple lines and contain SGR control
+sequences, since lines are measured by their visible width with
+termcols.Width.
+
+A Box is configured through its fields: the border drawn around the text, the
+padding between the border and the text, the margin around the border, the
+title and the footer set into the border, the attributes of the border and
+the background fill of the whole panel.
+
+# Usage
+
+	package main
+
+	import (
+		"fmt"
+
+		"github.com/mdm-code/termcols"
+		"github.com/mdm-code/termcols/box"
+	)
+
+	func main() {
+		b := box.Box{
+			Border:      box.Rounded,
+			BorderStyle: []termcols.SgrAttr{termcols.YellowFg},
+			Padding:     box.Spacing{Left: 1, Right: 1},
+			Title:       "Warning",
+		}
+		fmt.Println(b.Render("Disk space is running low.\nFree up some space."))
+	}
+*/
+package box
+
+import (
+	"strings"
+
+	"github.com/mdm-code/termcols"
+)
+
+// Border styles
+var (
+	None    = Border{}
+	Single  = Border{"─", "│", "┌", "┐", "└", "┘"}
+	Double  = Border{"═", "║", "╔", "╗", "╚", "╝"}
+	Rounded = Border{"─", "│", "╭", "╮", "╰", "╯"}
+	Heavy   = Border{"━", "┃", "┏", "┓", "┗", "┛"}
+	ASCII   = Border{"-", "|", "+", "+", "+", "+"}
+)
+
+// Border holds the strings that make up the border of a box, each one taking
+// up a single terminal cell. The None border draws no border at all.
+type Border struct {
+	Horizontal  string
+	Vertical    string
+	TopLeft     string
+	TopRight    string
+	BottomLeft  string
+	BottomRight string
+}
+
+// Spacing is the number of cells on each side of a box used for its padding
+// and margin. Top and Bottom are counted in lines and Left and Right in
+// columns.
+type Spacing struct {
+	Top, Right, Bottom, Left int
+}
+
+// Box draws a border around text. The zero value only pads lines of text to
+// the same width.
+//
+// The Fill attributes, usually a background color, cover the whole panel:
+// the border, the padding and the text. They are restored after control
+// sequences in the text that reset the background, so the fill is not cut
+// short by colorized parts of the text.
+type Box struct {
+	Border      Border
+	BorderStyle []termcols.SgrAttr
+	Fill        []termcols.SgrAttr
+	Padding     Spacing
+	Margin      Spacing
+	Title       string
+	Footer      string
+
+	// Width is the width of the text area. Longer lines are wrapped to fit
+	// with termcols.Wrap. With no Width, the text area is as wide as its
+	// longest line.
+	Width int
+}
+
+// Render returns the string text drawn in the box. Lines are separated with
+// line breaks, and there is no line break at the end.
+func (b Box) Render(text string) string {
+	if b.Width > 0 {
+		text = termcols.Wrap(text, b.Width)
+	}
+	lines := strings.Split(text, "\n")
+	width := b.Width
+	for _, l := range lines {
+		width = max(width, termcols.Width(l))
+	}
+	inner := b.Padding.Left + width + b.Padding.Right
+	if b.hasBorder() {
+		// NOTE: The title and the footer are set off the corner with a single
+		// horizontal line and surrounded with spaces.
+		for _, label := range []string{b.Title, b.Footer} {
+			if label != "" {
+				inner = max(inner, termcols.Width(label)+3)
+			}
+		}
+	}
+
+	var out []string
+	margin := strings.Repeat(" ", b.Margin.Left)
+	marginRight := strings.Repeat(" ", b.Margin.Right)
+	for i := 0; i < b.Margin.Top; i++ {
+		out = append(out, "")
+	}
+	add := func(s string) {
+		out = append(out, margin+s+marginRight)
+	}
+	if b.hasBorder() {
+		add(b.rule(b.Border.TopLeft, b.Title, b.Border.TopRight, inner))
+	}
+	blank := strings.Repeat(" ", inner)
+	for i := 0; i < b.Padding.Top; i++ {
+		add(b.side() + termcols.Colorize(blank, b.Fill...) + b.side())
+	}
+	// NOTE: Only the background of the fill is restored after the control
+	// sequences of the text, which is free to pick its own colors and styles.
+	fill := termcols.State{Bg: termcols.NewState(b.Fill...).Bg}
+	for _, l := range lines {
+		s := strings.Repeat(" ", b.Padding.Left) + termcols.Reapply(l, fill) +
+			strings.Repeat(" ", inner-b.Padding.Left-termcols.Width(l))
+		add(b.side() + termcols.Colorize(s, b.Fill...) + b.side())
+	}
+	for i := 0; i < b.Padding.Bottom; i++ {
+		add(b.side() + termcols.Colorize(blank, b.Fill...) + b.side())
+	}
+	if b.hasBorder() {
+		add(b.rule(b.Border.BottomLeft, b.Footer, b.Border.BottomRight, inner))
+	}
+	for i := 0; i < b.Margin.Bottom; i++ {
+		out = append(out, "")
+	}
+	return strings.Join(out, "\n")
+}
+
+// HasBorder reports whether the box draws its border.
+func (b Box) hasBorder() bool {
+	return b.Border != None
+}
+
+// Side returns the styled vertical border.
+func (b Box) side() string {
+	return b.styled(b.Border.Vertical)
+}
+
+// Styled returns the border string s with the fill and the border style.
+func (b Box) styled(s string) string {
+	if s == "" {
+		return ""
+	}
+	attrs := append(append([]termcols.SgrAttr{}, b.Fill...), b.BorderStyle...)
+	return termcols.Colorize(s, attrs...)
+}
+
+// Rule returns a horizontal border of width cells between the left and the
+// right corners with the label set into it.
+func (b Box) rule(left, label, right string, width int) string {
+	h := b.Border.Horizontal
+	if label == "" {
+		return b.styled(left + strings.Repeat(h, width) + right)
+	}
+	rest := width - termcols.Width(label) - 3
+	return b.styled(left+h+" ") + termcols.Colorize(label, b.Fill...) +
+		b.styled(" "+strings.Repeat(h, rest)+right)
+}
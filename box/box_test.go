@@ -0,0 +1,100 @@
+package box
+
+import (
+	"testing"
+
+	"github.com/mdm-code/termcols"
+)
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		name string
+		box  Box
+		text string
+		exp  string
+	}{
+		{"zero", Box{}, "plain\ntext", "plain\ntext "},
+		{"single", Box{Border: Single}, "a\nbcd", "┌───┐\n│a  │\n│bcd│\n└───┘"},
+		{"ascii", Box{Border: ASCII}, "x", "+-+\n|x|\n+-+"},
+		{"double", Box{Border: Double}, "x", "╔═╗\n║x║\n╚═╝"},
+		{"heavy", Box{Border: Heavy}, "x", "┏━┓\n┃x┃\n┗━┛"},
+		{
+			"padding",
+			Box{Border: Rounded, Padding: Spacing{1, 2, 1, 1}},
+			"x",
+			"╭────╮\n│    │\n│ x  │\n│    │\n╰────╯",
+		},
+		{
+			"margin",
+			Box{Border: ASCII, Margin: Spacing{1, 1, 1, 2}},
+			"x",
+			"\n  +-+ \n  |x| \n  +-+ \n",
+		},
+		{
+			"title",
+			Box{Border: ASCII, Title: "T", Footer: "end"},
+			"hello world",
+			"+- T -------+\n|hello world|\n+- end -----+",
+		},
+		{
+			"long-title",
+			Box{Border: ASCII, Title: "Title"},
+			"x",
+			"+- Title +\n|x       |\n+--------+",
+		},
+		{
+			"colored-text",
+			Box{Border: ASCII},
+			termcols.Colorize("ab", termcols.RedFg) + "\nc",
+			"+--+\n|" + termcols.Colorize("ab", termcols.RedFg) + "|\n|c |\n+--+",
+		},
+		{
+			"width",
+			Box{Border: ASCII, Width: 5},
+			"one two three",
+			"+-----+\n|one  |\n|two  |\n|three|\n+-----+",
+		},
+		{
+			"wide",
+			Box{Border: ASCII},
+			"日本\nabc",
+			"+----+\n|日本|\n|abc |\n+----+",
+		},
+		{
+			"no-border-padding",
+			Box{Padding: Spacing{Left: 2}},
+			"x\nyy",
+			"  x \n  yy",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if out := c.box.Render(c.text); out != c.exp {
+				t.Errorf("Have:\n%s\nwant:\n%s", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestRenderStyles(t *testing.T) {
+	b := Box{
+		Border:      ASCII,
+		BorderStyle: []termcols.SgrAttr{termcols.RedFg},
+		Fill:        []termcols.SgrAttr{termcols.BlueBg},
+		Title:       "T",
+	}
+	red, blue := termcols.RedFg, termcols.BlueBg
+	exp := termcols.Colorize("+- ", blue, red) + termcols.Colorize("T", blue) + termcols.Colorize(" +", blue, red) + "\n" +
+		termcols.Colorize("|", blue, red) + termcols.Colorize("x   ", blue) + termcols.Colorize("|", blue, red) + "\n" +
+		termcols.Colorize("+----+", blue, red)
+	if out := b.Render("x"); out != exp {
+		t.Errorf("Have: %q, want: %q", out, exp)
+	}
+
+	// NOTE: The fill is restored after the reset closing colorized text.
+	b.Border, b.Title = None, ""
+	exp = termcols.Colorize(termcols.Colorize("x", termcols.GreenFg)+string(blue)+"   ", blue)
+	if out := b.Render(termcols.Colorize("x", termcols.GreenFg) + "   "); out != exp {
+		t.Errorf("Have: %q, want: %q", out, exp)
+	}
+}
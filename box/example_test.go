@@ -0,0 +1,22 @@
+package box_test
+
+import (
+	"fmt"
+
+	"github.com/mdm-code/termcols/box"
+)
+
+// ExampleBox shows how to draw a banner with a title and padding.
+func ExampleBox() {
+	b := box.Box{
+		Border:  box.Rounded,
+		Padding: box.Spacing{Left: 1, Right: 1},
+		Title:   "Warning",
+	}
+	fmt.Println(b.Render("Disk space is running low.\nFree up some space."))
+	// Output:
+	// ╭─ Warning ──────────────────╮
+	// │ Disk space is running low. │
+	// │ Free up some space.        │
+	// ╰────────────────────────────╯
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/mdm-code/termcols/box"
+)
+
+var (
+	errBox error = errors.New("box must be one of single, double, rounded, heavy or ascii")
+	boxed  bool
+	boxes  = map[string]box.Border{
+		"single":  box.Single,
+		"double":  box.Double,
+		"rounded": box.Rounded,
+		"heavy":   box.Heavy,
+		"ascii":   box.ASCII,
+	}
+	boxBorder box.Border
+)
+
+// BoxReader reads the whole text of r and yields it drawn in a box.
+type boxReader struct {
+	r    io.Reader
+	b    box.Box
+	text *strings.Reader
+}
+
+// SetBox sets the border of the box drawn around text.
+func setBox(v string) error {
+	b, ok := boxes[strings.ToLower(v)]
+	if !ok {
+		return errBox
+	}
+	boxed, boxBorder = true, b
+	return nil
+}
+
+// NewBoxReader returns the reader yielding the text of r in a box with the
+// border b and a single column of padding on each side.
+func newBoxReader(r io.Reader, b box.Border) *boxReader {
+	return &boxReader{r: r, b: box.Box{Border: b, Padding: box.Spacing{Left: 1, Right: 1}}}
+}
+
+// Read reads the text of the underlying reader on the first call, since the
+// box can only be drawn once the width of all lines is known.
+func (br *boxReader) Read(p []byte) (int, error) {
+	if br.text == nil {
+		data, err := io.ReadAll(br.r)
+		if err != nil {
+			return 0, err
+		}
+		text := strings.TrimSuffix(string(data), "\n")
+		br.text = strings.NewReader(br.b.Render(text) + "\n")
+	}
+	return br.text.Read(p)
+}
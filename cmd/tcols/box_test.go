@@ -0,0 +1,74 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/mdm-code/termcols"
+	"github.com/mdm-code/termcols/box"
+)
+
+func TestSetBox(t *testing.T) {
+	cases := []struct {
+		v      string
+		border box.Border
+		err    error
+	}{
+		{"single", box.Single, nil},
+		{"Rounded", box.Rounded, nil},
+		{"ASCII", box.ASCII, nil},
+		{"dotted", box.None, errBox},
+	}
+	for _, c := range cases {
+		t.Run(c.v, func(t *testing.T) {
+			defer func() { boxed, boxBorder = false, box.None }()
+			err := setBox(c.v)
+			if err != c.err || boxBorder != c.border || boxed != (c.err == nil) {
+				t.Errorf("Have %v %v %t; want %v %v", boxBorder, err, boxed, c.border, c.err)
+			}
+		})
+	}
+}
+
+func TestBoxReader(t *testing.T) {
+	cases := []struct {
+		name string
+		r    io.Reader
+		want string
+	}{
+		{"text", strings.NewReader("hello\nworld!\n"), "+--------+\n| hello  |\n| world! |\n+--------+\n"},
+		{"one-byte", iotest.OneByteReader(strings.NewReader("ab")), "+----+\n| ab |\n+----+\n"},
+		{"empty", strings.NewReader(""), "+--+\n|  |\n+--+\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out, err := io.ReadAll(newBoxReader(c.r, box.ASCII))
+			if err != nil {
+				t.Fatalf("Have %v; want nil", err)
+			}
+			if have := string(out); have != c.want {
+				t.Errorf("Have %q; want %q", have, c.want)
+			}
+		})
+	}
+}
+
+func TestBoxReaderError(t *testing.T) {
+	if _, err := io.ReadAll(newBoxReader(&failReader{}, box.ASCII)); err == nil {
+		t.Error("Have nil; want error")
+	}
+}
+
+func TestPipeBox(t *testing.T) {
+	w := &mockWriter{}
+	r := newBoxReader(strings.NewReader("hi\n"), box.ASCII)
+	if err := pipe(r, w, []string{"redfg"}, true); err != nil {
+		t.Fatalf("Have %v; want nil", err)
+	}
+	want := string(termcols.RedFg) + "+----+\n| hi |\n+----+\n" + string(termcols.Reset)
+	if have := w.String(); have != want {
+		t.Errorf("Have %q; want %q", have, want)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mdm-code/termcols"
+)
+
+const contrastCmd = "contrast"
+
+var (
+	errContrastArgs error = errors.New("contrast takes exactly two colors: fg and bg")
+	errColorValue   error = errors.New("color must be a single color of the style grammar")
+	contrastUsage         = `tcols contrast - check whether text colors are readable
+
+Contrast reports the WCAG 2.x contrast ratio between the foreground color fg
+and the background color bg along with whether it passes the AA and AAA
+levels for normal and large text. It also reports the APCA lightness contrast
+Lc considered for WCAG 3.
+
+Colors are given the same way as a single color of the --style flag, for
+instance as #rgb or #rrggbb hex values, 256-color palette indices, grey0 to
+grey23, the names black, red, green, yellow, blue, magenta, cyan and white
+with an optional bright- prefix, or rgb8=, rgb24= and okabeito= patterns. The
+layer of the color is ignored, so bg=blue and bluebg are the same as blue.
+The 16 named colors are approximated with the xterm defaults.
+
+Usage:
+	tcols contrast [--color auto|always|never] fg bg
+
+Options:
+	-h, --help   show this help message and exit
+	    --color  when to colorize text: auto, always or never
+
+Example:
+	tcols contrast '#777' white
+`
+	contrastLevels = [...]struct {
+		name  string
+		ratio float64
+	}{
+		{"AA", termcols.ContrastAA},
+		{"AA large", termcols.ContrastAALarge},
+		{"AAA", termcols.ContrastAAA},
+		{"AAA large", termcols.ContrastAAALarge},
+	}
+)
+
+// ParseContrast parses command-line arguments of the contrast command into
+// the foreground and the background color.
+func parseContrast(args []string) (termcols.Color, termcols.Color, error) {
+	fs := flag.NewFlagSet("tcols contrast", flag.ExitOnError)
+	colorFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), contrastUsage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return termcols.Color{}, termcols.Color{}, err
+	}
+	if fs.NArg() != 2 {
+		return termcols.Color{}, termcols.Color{}, errContrastArgs
+	}
+	fg, err := parseColor(fs.Arg(0))
+	if err != nil {
+		return termcols.Color{}, termcols.Color{}, err
+	}
+	bg, err := parseColor(fs.Arg(1))
+	if err != nil {
+		return termcols.Color{}, termcols.Color{}, err
+	}
+	return fg, bg, nil
+}
+
+// ParseColor parses the color s with termcols.ParseColor.
+func parseColor(s string) (termcols.Color, error) {
+	c, err := termcols.ParseColor(s)
+	if err != nil {
+		return termcols.Color{}, fmt.Errorf("%q: %w", s, errColorValue)
+	}
+	return c, nil
+}
+
+// Contrast writes the contrast report of the colors given in args to w. The
+// sample text is colorized when the color mode and whether w isTerm terminal
+// call for it.
+func contrast(args []string, w io.Writer, isTerm bool) error {
+	fg, bg, err := parseContrast(args)
+	if err != nil {
+		return err
+	}
+	ratio := termcols.Contrast(fg, bg)
+	var b strings.Builder
+	fmt.Fprintf(&b, "Foreground  %s\n", fg.Hex())
+	fmt.Fprintf(&b, "Background  %s\n", bg.Hex())
+	if shouldColor(isTerm) {
+		sample := termcols.Colorize(" The quick brown fox ", fg.Fg(), bg.Bg())
+		fmt.Fprintf(&b, "Sample      %s\n", sample)
+	}
+	fmt.Fprintf(&b, "Contrast    %.2f:1\n", ratio)
+	fmt.Fprintf(&b, "APCA Lc     %.1f\n", termcols.APCA(fg, bg))
+	for _, l := range contrastLevels {
+		verdict := "fail"
+		if ratio >= l.ratio {
+			verdict = "pass"
+		}
+		fmt.Fprintf(&b, "%-11s %s (%.1f:1)\n", l.name, verdict, l.ratio)
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return errPiping
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mdm-code/termcols"
+)
+
+func TestParseColor(t *testing.T) {
+	cases := []struct {
+		s   string
+		exp termcols.Color
+		err error
+	}{
+		{"#777", termcols.Color{R: 119, G: 119, B: 119}, nil},
+		{"#FF8700", termcols.Color{R: 255, G: 135, B: 0}, nil},
+		{"214", termcols.Color{R: 255, G: 175, B: 0}, nil},
+		{"0", termcols.Color{}, nil},
+		{"white", termcols.Color{R: 229, G: 229, B: 229}, nil},
+		{"Bright-White", termcols.Color{R: 255, G: 255, B: 255}, nil},
+		{"bright-blue", termcols.Color{R: 92, G: 92, B: 255}, nil},
+		{"grey23", termcols.Color{R: 238, G: 238, B: 238}, nil},
+		{"bg=#000", termcols.Color{}, nil},
+		{"rgb24=bg:1:2:3", termcols.Color{R: 1, G: 2, B: 3}, nil},
+
+		{"#77", termcols.Color{}, errColorValue},
+		{"256", termcols.Color{}, errColorValue},
+		{"-1", termcols.Color{}, errColorValue},
+		{"purple", termcols.Color{}, errColorValue},
+		{"bright-", termcols.Color{}, errColorValue},
+		{"bold", termcols.Color{}, errColorValue},
+		{"", termcols.Color{}, errColorValue},
+	}
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			out, err := parseColor(c.s)
+			if !errors.Is(err, c.err) || out != c.exp {
+				t.Errorf("Have %v %v; want %v %v", out, err, c.exp, c.err)
+			}
+		})
+	}
+}
+
+func TestContrast(t *testing.T) {
+	cases := []struct {
+		name     string
+		args     []string
+		isTerm   bool
+		contains []string
+		excludes []string
+		err      error
+	}{
+		{
+			"gray-on-white",
+			[]string{"#777", "#fff"},
+			false,
+			[]string{
+				"Foreground  #777777\n",
+				"Background  #ffffff\n",
+				"Contrast    4.48:1\n",
+				"APCA Lc     71.",
+				"AA          fail (4.5:1)\n",
+				"AA large    pass (3.0:1)\n",
+				"AAA         fail (7.0:1)\n",
+				"AAA large   fail (4.5:1)\n",
+			},
+			[]string{termcols.Esc, "Sample"},
+			nil,
+		},
+		{
+			"black-on-white",
+			[]string{"0", "bright-white"},
+			true,
+			[]string{
+				"Contrast    21.00:1\n",
+				"AAA         pass (7.0:1)\n",
+				"Sample      " + string(termcols.Rgb24(termcols.FG, 0, 0, 0)),
+			},
+			[]string{"fail"},
+			nil,
+		},
+		{
+			"color-never",
+			[]string{"--color", "never", "red", "blue"},
+			true,
+			[]string{"Contrast"},
+			[]string{termcols.Esc},
+			nil,
+		},
+		{"one-color", []string{"red"}, false, nil, nil, errContrastArgs},
+		{"three-colors", []string{"red", "blue", "green"}, false, nil, nil, errContrastArgs},
+		{"bad-fg", []string{"reed", "blue"}, false, nil, nil, errColorValue},
+		{"bad-bg", []string{"red", "#blue"}, false, nil, nil, errColorValue},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			colorMode = colorAuto
+			w := &mockWriter{}
+			err := contrast(c.args, w, c.isTerm)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Have %v; want %v", err, c.err)
+			}
+			out := w.String()
+			for _, s := range c.contains {
+				if !strings.Contains(out, s) {
+					t.Errorf("Output misses %q", s)
+				}
+			}
+			for _, s := range c.excludes {
+				if strings.Contains(out, s) {
+					t.Errorf("Output contains %q", s)
+				}
+			}
+		})
+	}
+	colorMode = colorAuto
+}
+
+func TestContrastFail(t *testing.T) {
+	if err := contrast([]string{"red", "blue"}, &failWriter{}, true); err != errPiping {
+		t.Errorf("Have %v; want %v", err, errPiping)
+	}
+}
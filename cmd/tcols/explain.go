@@ -0,0 +1,93 @@
+package main
+
+import (
+	"io"
+	"strings"
+
+	"github.com/mdm-code/termcols"
+)
+
+var explained bool
+
+// ExplainReader yields the text of r with each control sequence replaced by
+// the names of the attributes it sets in square brackets.
+type explainReader struct {
+	r     io.Reader
+	buf   []byte
+	carry []byte
+	out   []byte
+	err   error
+}
+
+// NewExplainReader returns the reader explaining control sequences in r.
+func newExplainReader(r io.Reader) *explainReader {
+	return &explainReader{r: r, buf: make([]byte, chunkSize)}
+}
+
+// Read reads the next chunk of the underlying reader and explains it. A
+// control sequence cut off at the end of the chunk is held back until the
+// next chunk arrives.
+func (er *explainReader) Read(p []byte) (int, error) {
+	for len(er.out) == 0 {
+		if er.err != nil {
+			return 0, er.err
+		}
+		n, err := er.r.Read(er.buf)
+		data := append(er.carry, er.buf[:n]...)
+		er.out, er.carry = explain(er.out[:0], data, err != nil)
+		er.err = err
+	}
+	n := copy(p, er.out)
+	er.out = er.out[n:]
+	return n, nil
+}
+
+// Explain appends data to dst with control sequences explained, and returns
+// the part of data that has to wait for more input. It is split with
+// termcols.ScanSequences, and nothing is held back when atEOF is set.
+func explain(dst, data []byte, atEOF bool) ([]byte, []byte) {
+	for len(data) > 0 {
+		n, tok, _ := termcols.ScanSequences(data, atEOF)
+		if n == 0 {
+			break
+		}
+		if tok[0] == termcols.Esc[0] {
+			dst = append(dst, explainSeq(string(tok))...)
+		} else {
+			dst = append(dst, tok...)
+		}
+		data = data[n:]
+	}
+	return dst, data
+}
+
+// ExplainSeq names the attributes set by the control sequence seq. SGR
+// parameters are named the way they are written with the --style flag, and
+// parameters, control sequences and escape characters that tcols does not
+// understand are flagged as unsupported.
+func explainSeq(seq string) string {
+	if body, ok := strings.CutPrefix(seq, termcols.Osc); ok {
+		body = strings.TrimSuffix(strings.TrimSuffix(body, termcols.St), "\a")
+		return "[unsupported:osc " + body + "]"
+	}
+	if !strings.HasPrefix(seq, termcols.Csi) {
+		return "[unsupported:esc]"
+	}
+	body := seq[len(termcols.Csi):]
+	if !strings.HasSuffix(seq, "m") {
+		return "[unsupported:csi " + body + "]"
+	}
+	parts, _ := termcols.SgrAttr(seq).Split()
+	var b strings.Builder
+	for _, a := range parts {
+		name, ok := termcols.OffName(a)
+		if !ok {
+			name, ok = termcols.Name(a)
+		}
+		if !ok {
+			name = "unsupported:sgr " + string(a[len(termcols.Csi):len(a)-1])
+		}
+		b.WriteString("[" + name + "]")
+	}
+	return b.String()
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/mdm-code/termcols"
+)
+
+func TestExplainSeq(t *testing.T) {
+	cases := []struct {
+		name string
+		seq  string
+		want string
+	}{
+		{"reset", string(termcols.Reset), "[reset]"},
+		{"empty", "\033[m", "[reset]"},
+		{"combined", "\033[1;34;48;2;57;124;12m", "[bold][bluefg][rgb24=bg:57:124:12]"},
+		{"rgb8", string(termcols.Rgb8(termcols.FG, 196)), "[rgb8=fg:196]"},
+		{"bright", "\033[93;104m", "[yellowbfg][bluebbg]"},
+		{"default", "\033[39;49;10m", "[defaultfg][defaultbg][defaultstyle]"},
+		{"off", "\033[22;23;24;25;27;28;29m", "[no-bold][no-italic][no-underline][no-blink][no-reverse][no-hide][no-strike]"},
+		{"unknown", "\033[53;1m", "[unsupported:sgr 53][bold]"},
+		{"bad-rgb8", "\033[38;5;300;1m", "[unsupported:sgr 38;5;300][bold]"},
+		{"bad-mode", "\033[38;7;1m", "[unsupported:sgr 38;7][bold]"},
+		{"short", "\033[48;2;1m", "[unsupported:sgr 48;2;1]"},
+		{"lone-layer", "\033[38m", "[unsupported:sgr 38]"},
+		{"malformed", "\033[1:2m", "[unsupported:sgr 1:2]"},
+		{"csi", "\033[2K", "[unsupported:csi 2K]"},
+		{"osc", "\033]0;title\a", "[unsupported:osc 0;title]"},
+		{"osc-st", "\033]8;;https://example.com\033\\", "[unsupported:osc 8;;https://example.com]"},
+		{"esc", "\033", "[unsupported:esc]"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if have := explainSeq(c.seq); have != c.want {
+				t.Errorf("Have %q; want %q", have, c.want)
+			}
+		})
+	}
+}
+
+func TestExplainReader(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "no colors here", "no colors here"},
+		{"colorize", termcols.Colorize("Hello", termcols.Bold, termcols.BlueFg), "[bold][bluefg]Hello[reset]"},
+		{"unterminated", "a\033[31", "a[unsupported:esc][31"},
+		{"lone-esc", "a\033", "a[unsupported:esc]"},
+		{"osc", "a\033]0;t\033\\b", "a[unsupported:osc 0;t]b"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// NOTE: Reading one byte at a time cuts every control sequence
+			// between chunks.
+			for _, r := range []io.Reader{strings.NewReader(c.in), iotest.OneByteReader(strings.NewReader(c.in))} {
+				out, err := io.ReadAll(newExplainReader(r))
+				if err != nil {
+					t.Fatalf("Have %v; want nil", err)
+				}
+				if have := string(out); have != c.want {
+					t.Errorf("Have %q; want %q", have, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestExplainReaderError(t *testing.T) {
+	errRead := errors.New("read failed")
+	r := io.MultiReader(strings.NewReader("a\033[1"), iotest.ErrReader(errRead))
+	out, err := io.ReadAll(newExplainReader(r))
+	if !errors.Is(err, errRead) {
+		t.Fatalf("Have %v; want %v", err, errRead)
+	}
+	if want := "a[unsupported:esc][1"; string(out) != want {
+		t.Errorf("Have %q; want %q", out, want)
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mdm-code/termcols"
+	"github.com/mdm-code/termcols/sixel"
+)
+
+const (
+	imageCmd          = "image"
+	defaultImageWidth = 80
+)
+
+var (
+	errImageArgs    error = errors.New("image takes exactly one file")
+	errImageProfile error = errors.New("profile must be one of auto, ansi, ansi256 or truecolor")
+	errImageColor   error = errors.New("image cannot be rendered with colors turned off")
+	errImageDecode  error = errors.New("unsupported or malformed image")
+	errImageColors  error = errors.New("colors must be between 2 and 256")
+	imageUsage            = `tcols image - show an image on the terminal
+
+Image renders a PNG, JPEG or GIF image with half block characters, so that
+each terminal cell shows two pixels in its foreground and background colors.
+The image is resized to the given width, and its height is scaled to keep the
+aspect ratio. By default, the image is as wide as the terminal, but never
+wider than the image itself. The file name - stands for the standard input.
+
+Colors are downgraded to the 256-color palette or the 16 ANSI colors when the
+terminal does not support 24-bit colors. The color profile is detected from
+the environment unless it is set with the --profile flag.
+
+With the --sixel flag, the image is written as Sixel graphics instead, which
+terminals supporting them display as real pixels. The image is quantized to
+at most --colors colors with dithering. The width is then given in pixels,
+and the image keeps its own size by default.
+
+Usage:
+	tcols image [-w|--width n] [--profile auto|ansi|ansi256|truecolor]
+	            [--sixel [--colors n]] [--color auto|always|never] file
+
+Options:
+	-h, --help     show this help message and exit
+	-w, --width    width of the image in terminal columns, or pixels with --sixel
+	    --profile  color profile used to render the image (default auto)
+	    --sixel    write the image as Sixel graphics
+	    --colors   size of the Sixel palette from 2 to 256 (default 256)
+	    --color    when to colorize text: auto, always or never
+
+Example:
+	tcols image --width 40 logo.png
+	tcols image --sixel --colors 64 photo.jpg
+`
+	imageProfiles = map[string]termcols.Profile{
+		"ansi":      termcols.ANSI,
+		"ansi256":   termcols.ANSI256,
+		"truecolor": termcols.TrueColor,
+	}
+)
+
+// ImageOpts holds options of the image command.
+type imageOpts struct {
+	width   int
+	profile termcols.Profile
+	auto    bool
+	sixel   bool
+	colors  int
+	file    string
+}
+
+// ParseImage parses command-line arguments of the image command.
+func parseImage(args []string) (imageOpts, error) {
+	opts := imageOpts{auto: true, colors: sixel.MaxColors}
+	fs := flag.NewFlagSet("tcols image", flag.ExitOnError)
+	for _, fName := range []string{"w", "width"} {
+		fs.IntVar(&opts.width, fName, 0, "width of the image in terminal columns")
+	}
+	fs.BoolVar(&opts.sixel, "sixel", false, "write the image as Sixel graphics")
+	fs.IntVar(&opts.colors, "colors", sixel.MaxColors, "size of the Sixel palette")
+	fs.Func("profile", "color profile used to render the image", func(v string) error {
+		v = strings.ToLower(v)
+		if v == "auto" {
+			opts.auto = true
+			return nil
+		}
+		p, ok := imageProfiles[v]
+		if !ok {
+			return errImageProfile
+		}
+		opts.profile, opts.auto = p, false
+		return nil
+	})
+	colorFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), imageUsage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() != 1 {
+		return opts, errImageArgs
+	}
+	if opts.colors < 2 || opts.colors > sixel.MaxColors {
+		return opts, errImageColors
+	}
+	opts.file = fs.Arg(0)
+	return opts, nil
+}
+
+// RenderImage writes the image given in args to w. The detected profile is
+// used unless the profile option is set, and the image is fit to termWidth
+// unless the width option is set. Whether w isTerm terminal decides along
+// with the color mode whether colors are allowed at all.
+func renderImage(args []string, w io.Writer, isTerm bool, termWidth int, detected termcols.Profile) error {
+	opts, err := parseImage(args)
+	if err != nil {
+		return err
+	}
+	if opts.sixel {
+		return renderSixel(opts, w, isTerm)
+	}
+	profile := opts.profile
+	if opts.auto {
+		if !shouldColor(isTerm) {
+			return errImageColor
+		}
+		// NOTE: Colors forced with --color always on output that is not a
+		// terminal get the full range of colors.
+		profile = max(detected, termcols.ANSI)
+		if !isTerm {
+			profile = termcols.TrueColor
+		}
+	} else if colorMode == colorNever {
+		return errImageColor
+	}
+	img, err := decodeImage(opts.file)
+	if err != nil {
+		return err
+	}
+	width := opts.width
+	if width <= 0 {
+		width = termWidth
+		if width <= 0 {
+			width = defaultImageWidth
+		}
+		width = min(width, img.Bounds().Dx())
+	}
+	if _, err := io.WriteString(w, termcols.RenderImage(img, width, profile)); err != nil {
+		return errPiping
+	}
+	return nil
+}
+
+// RenderSixel writes the image given in opts to w as Sixel graphics. The
+// image is downscaled when the width option is smaller than the image.
+func renderSixel(opts imageOpts, w io.Writer, isTerm bool) error {
+	if !shouldColor(isTerm) {
+		return errImageColor
+	}
+	img, err := decodeImage(opts.file)
+	if err != nil {
+		return err
+	}
+	if opts.width > 0 && opts.width < img.Bounds().Dx() {
+		img = termcols.ScaleImage(img, opts.width, 0)
+	}
+	enc := sixel.Encoder{Colors: opts.colors}
+	if err := enc.Encode(w, img); err != nil {
+		if errors.Is(err, sixel.ErrEmpty) {
+			return fmt.Errorf("%s: %w", opts.file, errImageDecode)
+		}
+		return errPiping
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return errPiping
+	}
+	return nil
+}
+
+// DecodeImage decodes the image stored in the file name, or read from the
+// standard input when the name is -.
+func decodeImage(name string) (image.Image, error) {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, errImageDecode)
+	}
+	return img, nil
+}
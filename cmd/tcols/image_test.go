@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mdm-code/termcols"
+	"github.com/mdm-code/termcols/sixel"
+)
+
+// writeImage saves a width by height red PNG image in a temporary directory
+// and returns its path.
+func writeImage(t *testing.T, width, height int) string {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{255, 0, 0, 255})
+		}
+	}
+	name := filepath.Join(t.TempDir(), "red.png")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func TestParseImage(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		exp  imageOpts
+		err  error
+	}{
+		{"defaults", []string{"a.png"}, imageOpts{auto: true, colors: 256, file: "a.png"}, nil},
+		{"width", []string{"-w", "20", "a.png"}, imageOpts{width: 20, auto: true, colors: 256, file: "a.png"}, nil},
+		{"profile", []string{"--profile", "ANSI256", "a.png"}, imageOpts{profile: termcols.ANSI256, colors: 256, file: "a.png"}, nil},
+		{"profile-auto", []string{"--profile", "ansi", "--profile", "auto", "a.png"}, imageOpts{profile: termcols.ANSI, auto: true, colors: 256, file: "a.png"}, nil},
+		{"sixel", []string{"--sixel", "--colors", "16", "a.png"}, imageOpts{auto: true, sixel: true, colors: 16, file: "a.png"}, nil},
+		{"colors-low", []string{"--colors", "1", "a.png"}, imageOpts{}, errImageColors},
+		{"colors-high", []string{"--colors", "257", "a.png"}, imageOpts{}, errImageColors},
+		{"no-file", []string{}, imageOpts{auto: true}, errImageArgs},
+		{"two-files", []string{"a.png", "b.png"}, imageOpts{auto: true}, errImageArgs},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts, err := parseImage(c.args)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Have %v; want %v", err, c.err)
+			}
+			if err == nil && opts != c.exp {
+				t.Errorf("Have %+v; want %+v", opts, c.exp)
+			}
+		})
+	}
+}
+
+func TestRenderImageCmd(t *testing.T) {
+	name := writeImage(t, 4, 4)
+	red24, red8 := string(termcols.Rgb24(termcols.FG, 255, 0, 0)), string(termcols.Rgb8(termcols.FG, 196))
+	bg24, bg8 := string(termcols.Rgb24(termcols.BG, 255, 0, 0)), string(termcols.Rgb8(termcols.BG, 196))
+	reset := string(termcols.Reset)
+	cases := []struct {
+		name      string
+		args      []string
+		isTerm    bool
+		termWidth int
+		detected  termcols.Profile
+		want      string
+		err       error
+	}{
+		{"auto", []string{"-w", "2", name}, true, 80, termcols.ANSI256, red8 + bg8 + "▀▀" + reset + "\n", nil},
+		{"term-width", []string{name}, true, 2, termcols.TrueColor, red24 + bg24 + "▀▀" + reset + "\n", nil},
+		{"image-width", []string{name}, true, 80, termcols.TrueColor, strings.Repeat(red24+bg24+"▀▀▀▀"+reset+"\n", 2), nil},
+		{"profile", []string{"--profile", "truecolor", "-w", "2", name}, false, 0, termcols.Plain, red24 + bg24 + "▀▀" + reset + "\n", nil},
+		{"always", []string{"--color", "always", "-w", "2", name}, false, 0, termcols.Plain, red24 + bg24 + "▀▀" + reset + "\n", nil},
+		{"not-term", []string{name}, false, 0, termcols.Plain, "", errImageColor},
+		{"never", []string{"--color", "never", "--profile", "ansi", name}, true, 80, termcols.ANSI, "", errImageColor},
+		{"missing", []string{filepath.Join(t.TempDir(), "missing.png")}, true, 80, termcols.ANSI, "", os.ErrNotExist},
+		{"not-image", []string{"image_test.go"}, true, 80, termcols.ANSI, "", errImageDecode},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			defer func() { colorMode = colorAuto }()
+			var b strings.Builder
+			err := renderImage(c.args, &b, c.isTerm, c.termWidth, c.detected)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Have %v; want %v", err, c.err)
+			}
+			if have := b.String(); have != c.want {
+				t.Errorf("Have %q; want %q", have, c.want)
+			}
+		})
+	}
+}
+
+func TestRenderSixelCmd(t *testing.T) {
+	name := writeImage(t, 4, 4)
+	sixels := func(width, height int, band string) string {
+		return sixel.Start + fmt.Sprintf(`"1;1;%d;%d#0;2;100;0;0#0%s`, width, height, band) + sixel.End + "\n"
+	}
+	cases := []struct {
+		name   string
+		args   []string
+		isTerm bool
+		want   string
+		err    error
+	}{
+		{"term", []string{"--sixel", name}, true, sixels(4, 4, "!4N"), nil},
+		{"width", []string{"--sixel", "-w", "2", name}, true, sixels(2, 2, "BB"), nil},
+		{"upscale", []string{"--sixel", "-w", "8", name}, true, sixels(4, 4, "!4N"), nil},
+		{"always", []string{"--sixel", "--color", "always", name}, false, sixels(4, 4, "!4N"), nil},
+		{"not-term", []string{"--sixel", name}, false, "", errImageColor},
+		{"never", []string{"--sixel", "--color", "never", name}, true, "", errImageColor},
+		{"not-image", []string{"--sixel", "image_test.go"}, true, "", errImageDecode},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			defer func() { colorMode = colorAuto }()
+			var b strings.Builder
+			err := renderImage(c.args, &b, c.isTerm, 80, termcols.TrueColor)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Have %v; want %v", err, c.err)
+			}
+			if have := b.String(); have != c.want {
+				t.Errorf("Have %q; want %q", have, c.want)
+			}
+		})
+	}
+}
@@ -6,12 +6,34 @@ output.
 
 Usage:
 
-	tcols [-s|--style arg...] [file...]
+	tcols [-s|--style arg...] [-b|--background auto|light|dark]
+	      [--color auto|always|never] [--compact]
+	      [--simulate protanopia|deuteranopia|tritanopia|achromatopsia]
+	      [--box single|double|rounded|heavy|ascii] [--explain]
+	      [file...]
+	tcols palette [-l|--layer fg|bg] [-p|--plain] [--color auto|always|never]
+	tcols contrast [--color auto|always|never] fg bg
+	tcols table [--border style] [-a|--align list] [options...] < file
+	tcols image [-w|--width n] [--profile auto|ansi|ansi256|truecolor]
+	            [--sixel [--colors n]] file
+
+Commands:
+
+	palette   show the 256-color palette and truecolor strips
+	contrast  check the contrast between a foreground and a background color
+	table     render CSV or TSV from the standard input as a table
+	image     show a PNG, JPEG or GIF image with half blocks or Sixel graphics
 
 Options:
 
-	-h, --help   show this help message and exit
-	-s, --style  list of styles and colors to apply to text
+	-h, --help        show this help message and exit
+	-s, --style       list of styles and colors to apply to text
+	-b, --background  terminal background used to resolve adaptive colors
+	    --color       when to colorize text: auto, always or never
+	    --compact     merge styles into a single control sequence
+	    --simulate    rewrite colors as seen with a color vision deficiency
+	    --box         draw a box around the text of each file
+	    --explain     show control sequences of the input by name
 
 Example:
 
@@ -25,7 +47,46 @@ Output:
 The program returns text read from a file with Select Graphic Rendition control
 sequences prepended and the reset control sequence appended at the end. The
 sequence of attributes passed to the --style flag of the command is preserved,
-so colors and styles can (un)intentionally cancel out one another.
+so colors and styles can (un)intentionally cancel out one another. With the
+--compact flag, all of them are merged into a single control sequence, such as
+\033[1;34mHello, World!\033[0m for the example above.
+
+Just like with cat, texts of multiple files are written out in the order of
+the arguments, and the file name - stands for the standard input. Files are
+read ahead concurrently while the output is being written.
+
+Styles can also be written in a natural grammar, for instance
+'bold bright-red on blue', 'italic #ff0 on grey23' or
+'underline fg=red bg=default'. A color applies to the foreground unless it
+follows the word on or the bg= prefix. The full grammar is documented with
+MapColors of the termcols package.
+
+Adaptive colors of the form adaptive=light/dark, such as
+adaptive=bluefg/yellowfg, resolve to the light or the dark variant depending
+on the background of the terminal. The background is detected automatically
+unless it is set with the --background flag.
+
+The --simulate flag rewrites all colors of the input text and of the styles
+the way they are seen by people with the given color vision deficiency, which
+helps check whether colored output remains usable for them. Styles can use
+the colorblind-safe Okabe-Ito palette with okabeito=fg:name, where the name is
+one of black, orange, skyblue, bluishgreen, yellow, blue, vermillion or
+reddishpurple.
+
+The --box flag draws a box with the given border around the text of each
+file. Styles apply to the box as well as to the text inside.
+
+The --explain flag helps find out why colored text looks wrong. Instead of
+colorizing the text, it writes it out with every control sequence replaced
+by the names of the attributes it sets, the same ones the --style flag takes,
+such as [bold][bluefg][rgb24=bg:57:124:12]Hello[reset]. Sequences tcols
+does not understand are marked as unsupported.
+
+By default, text is colorized only when the standard output is a terminal.
+The --color flag, or the TCOLS_COLOR environment variable when the flag is not
+given, set to always forces colors, for instance when piping to less -R, and
+set to never disables them altogether. The flag applies to the usage text as
+well when it precedes the help flag.
 */
 package main
 
@@ -35,10 +96,11 @@ import (
 	"flag"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
+	"os/signal"
 	"strings"
 	"sync"
+	"syscall"
 
 	"github.com/mdm-code/termcols"
 	"golang.org/x/term"
@@ -49,10 +111,36 @@ const (
 	exitFailure
 )
 
+const (
+	// ChunkSize is the size of the buffer used to stream text from files.
+	chunkSize = 32 * 1024
+
+	// PrefetchFiles is the number of files read concurrently.
+	prefetchFiles = 4
+
+	// PrefetchChunks is the number of chunks buffered for each file.
+	prefetchChunks = 4
+
+	// StdinName is the file name that stands for the standard input.
+	stdinName = "-"
+)
+
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+	colorEnv    = "TCOLS_COLOR"
+)
+
 var (
-	styles     []string
-	errPiping  error = errors.New("cannot read/write on nil interfaces")
-	usageAttrs       = [...][2]string{
+	styles       []string
+	compact      bool
+	errPiping    error = errors.New("cannot read/write on nil interfaces")
+	errBg        error = errors.New("background must be one of auto, light or dark")
+	errColor     error = errors.New("color must be one of auto, always or never")
+	errInterrupt error = errors.New("interrupted")
+	colorMode          = colorAuto
+	usageAttrs         = [...][2]string{
 		{"Hello, world!", string(termcols.Bold) + string(termcols.BlueFg) + "%s" + string(termcols.Reset)},
 		{"bold", string(termcols.Bold) + "%s" + string(termcols.Reset)},
 		{"faint", string(termcols.Faint) + "%s" + string(termcols.Reset)},
@@ -108,11 +196,32 @@ Tcols reads text from a file and writes the colorized text to the standard
 output.
 
 Usage:
-	tcols [-s|--style arg...] [file...]
+	tcols [-s|--style arg...] [-b|--background auto|light|dark]
+	      [--color auto|always|never] [--compact]
+	      [--simulate protanopia|deuteranopia|tritanopia|achromatopsia]
+	      [--box single|double|rounded|heavy|ascii] [--explain]
+	      [file...]
+	tcols palette [-l|--layer fg|bg] [-p|--plain] [--color auto|always|never]
+	tcols contrast [--color auto|always|never] fg bg
+	tcols table [--border style] [-a|--align list] [options...] < file
+	tcols image [-w|--width n] [--profile auto|ansi|ansi256|truecolor]
+	            [--sixel [--colors n]] file
+
+Commands:
+	palette   show the 256-color palette and truecolor strips
+	contrast  check the contrast between a foreground and a background color
+	table     render CSV or TSV from the standard input as a table
+	image     show a PNG, JPEG or GIF image with half blocks or Sixel graphics
 
 Options:
-	-h, --help   show this help message and exit
-	-s, --style  list of styles and colors to apply to text
+	-h, --help        show this help message and exit
+	-s, --style       list of styles and colors to apply to text
+	-b, --background  terminal background used to resolve adaptive colors
+	    --color       when to colorize text: auto, always or never
+	    --compact     merge styles into a single control sequence
+	    --simulate    rewrite colors as seen with a color vision deficiency
+	    --box         draw a box around the text of each file
+	    --explain     show control sequences of the input by name
 
 Example:
 	tcols -style 'bold bluefg' < <(echo -n 'Hello, world!')
@@ -123,7 +232,45 @@ Output:
 The program returns text read from a file with Select Graphic Rendition control
 sequences prepended and the reset control sequence appended at the end. The
 sequence of attributes passed to the --style flag of the command is preserved,
-so colors and styles can (un)intentionally cancel out one another.
+so colors and styles can (un)intentionally cancel out one another. With the
+--compact flag, all of them are merged into a single control sequence.
+
+Just like with cat, texts of multiple files are written out in the order of
+the arguments, and the file name - stands for the standard input. Files are
+read ahead concurrently while the output is being written.
+
+Styles can also be written in a natural grammar, for instance
+'bold bright-red on blue', 'italic #ff0 on grey23' or
+'underline fg=red bg=default'. A color applies to the foreground unless it
+follows the word on or the bg= prefix. The full grammar is documented with
+MapColors of the termcols package.
+
+Adaptive colors of the form adaptive=light/dark, such as
+adaptive=bluefg/yellowfg, resolve to the light or the dark variant depending
+on the background of the terminal. The background is detected automatically
+unless it is set with the --background flag.
+
+The --simulate flag rewrites all colors of the input text and of the styles
+the way they are seen by people with the given color vision deficiency, which
+helps check whether colored output remains usable for them. Styles can use
+the colorblind-safe Okabe-Ito palette with okabeito=fg:name, where the name is
+one of black, orange, skyblue, bluishgreen, yellow, blue, vermillion or
+reddishpurple.
+
+The --box flag draws a box with the given border around the text of each
+file. Styles apply to the box as well as to the text inside.
+
+The --explain flag helps find out why colored text looks wrong. Instead of
+colorizing the text, it writes it out with every control sequence replaced
+by the names of the attributes it sets, the same ones the --style flag takes,
+such as [bold][bluefg][rgb24=bg:57:124:12]Hello[reset]. Sequences tcols
+does not understand are marked as unsupported.
+
+By default, text is colorized only when the standard output is a terminal.
+The --color flag, or the TCOLS_COLOR environment variable when the flag is not
+given, set to always forces colors, for instance when piping to less -R, and
+set to never disables them altogether. The flag applies to the usage text as
+well when it precedes the help flag.
 
 Styles:
 	%s %s %s %s
@@ -164,11 +311,21 @@ type (
 		w *bufio.Writer
 		sync.Mutex
 	}
+
+	flusher interface {
+		Flush() error
+	}
+
+	prefetcher struct {
+		chunks <-chan []byte
+		chunk  []byte
+		err    error
+	}
 )
 
 func (f *failer) fail(e error) (exitFunc, exitCode) {
 	f.mu.Lock()
-	fmt.Fprintf(f.w, e.Error())
+	fmt.Fprintln(f.w, e.Error())
 	f.mu.Unlock()
 	return f.fn, f.code
 }
@@ -181,6 +338,8 @@ func (cw *concurrentWriter) Write(p []byte) (n int, err error) {
 }
 
 func (cw *concurrentWriter) Flush() error {
+	cw.Lock()
+	defer cw.Unlock()
 	return cw.w.Flush()
 }
 
@@ -207,6 +366,9 @@ func prepUsageAttrs(colored bool) []any {
 }
 
 func parse(args []string, open openFn) ([]io.Reader, func(), error) {
+	if err := initColorMode(); err != nil {
+		return []io.Reader{}, func() {}, err
+	}
 	fs := flag.NewFlagSet("tcols", flag.ExitOnError)
 	for _, fName := range []string{"s", "style"} {
 		fs.Func(
@@ -218,9 +380,21 @@ func parse(args []string, open openFn) ([]io.Reader, func(), error) {
 			},
 		)
 	}
+	for _, fName := range []string{"b", "background"} {
+		fs.Func(
+			fName,
+			"terminal background used to resolve adaptive colors",
+			setBackground,
+		)
+	}
+	colorFlag(fs)
+	fs.BoolVar(&compact, "compact", false, "merge styles into a single control sequence")
+	fs.Func("simulate", "rewrite colors as seen with a color vision deficiency", setSimulation)
+	fs.Func("box", "draw a box around text", setBox)
+	fs.BoolVar(&explained, "explain", false, "show control sequences of the input by name")
 	fs.Usage = func() {
 		usageOut := os.Stdout
-		if term.IsTerminal(int(usageOut.Fd())) {
+		if shouldColor(term.IsTerminal(int(usageOut.Fd()))) {
 			colored := prepUsageAttrs(true)
 			fmt.Fprintf(usageOut, fmt.Sprintf(usage, colored...))
 			return
@@ -238,19 +412,88 @@ func parse(args []string, open openFn) ([]io.Reader, func(), error) {
 	return []io.Reader{os.Stdin}, func() {}, nil
 }
 
+// InitColorMode sets the color mode to the value of the TCOLS_COLOR
+// environment variable, or to auto when the variable is not set.
+func initColorMode() error {
+	colorMode = colorAuto
+	if v, ok := os.LookupEnv(colorEnv); ok && v != "" {
+		if err := setColorMode(v); err != nil {
+			return fmt.Errorf("%s: %w", colorEnv, err)
+		}
+	}
+	return nil
+}
+
+// SetColorMode sets the color mode to one of auto, always or never.
+func setColorMode(v string) error {
+	switch v = strings.ToLower(v); v {
+	case colorAuto, colorAlways, colorNever:
+		colorMode = v
+		return nil
+	}
+	return errColor
+}
+
+// ColorFlag registers the --color flag on the flag set fs.
+func colorFlag(fs *flag.FlagSet) {
+	fs.Func("color", "when to colorize text: auto, always or never", setColorMode)
+}
+
+// ShouldColor decides whether to colorize text depending on the color mode
+// and whether the output isTerm terminal.
+func shouldColor(isTerm bool) bool {
+	switch colorMode {
+	case colorAlways:
+		return true
+	case colorNever:
+		return false
+	}
+	return isTerm
+}
+
+// SetBackground configures the terminal background used to resolve adaptive
+// colors. The auto value leaves it up to the termcols package to detect it.
+func setBackground(v string) error {
+	switch strings.ToLower(v) {
+	case "auto":
+	case "light":
+		termcols.SetDarkBackground(false)
+	case "dark":
+		termcols.SetDarkBackground(true)
+	default:
+		return errBg
+	}
+	return nil
+}
+
 // Open opens files to have their contents read. The function f serves as the
-// main callable responsible for opening files.
+// main callable responsible for opening files. The file name - stands for the
+// standard input. Just like with cat, the standard input is read only once, so
+// it yields no text when - is repeated.
 func open(fnames []string, f func(string) (*os.File, error)) ([]io.Reader, func(), error) {
 	var files []io.Reader
 	closer := func() {
 		for _, f := range files {
+			if f == io.Reader(os.Stdin) {
+				continue
+			}
 			switch t := f.(type) {
 			case io.Closer:
 				t.Close()
 			}
 		}
 	}
+	var stdin bool
 	for _, fname := range fnames {
+		if fname == stdinName {
+			if stdin {
+				files = append(files, strings.NewReader(""))
+				continue
+			}
+			files = append(files, os.Stdin)
+			stdin = true
+			continue
+		}
 		f, err := f(fname)
 		if err != nil {
 			return files, closer, err
@@ -263,73 +506,231 @@ func open(fnames []string, f func(string) (*os.File, error)) ([]io.Reader, func(
 // Pipe transfers the input text colorized according to the provided styles
 // from the r reader to the w writer. The colorize parameter controls if the
 // text should be colorized.
+//
+// Text is streamed in chunks of chunkSize bytes, so it shows up in w as soon
+// as it arrives, and memory usage does not depend on the size of the input.
+// The styles prefix is written before the first chunk, and the reset control
+// sequence after the last one, even if reading fails halfway through. The
+// prefix is a single control sequence when compact is set.
 func pipe(r io.Reader, w io.Writer, styles []string, colorize bool) error {
-	if r == nil && w == nil {
-		return errPiping
-	}
-	text, err := ioutil.ReadAll(r)
-	if err != nil {
+	if r == nil || w == nil {
 		return errPiping
 	}
 	colors, err := termcols.MapColors(styles)
 	if err != nil {
 		return err
 	}
-	if colorize {
-		colored := termcols.Colorize(string(text), colors...)
-		_, err = io.WriteString(w, colored)
-		if err != nil {
+	var rw *rewriter
+	if simulation != 0 {
+		rw = &rewriter{d: simulation}
+		for i, c := range colors {
+			colors[i] = termcols.SimulateAttr(c, simulation)
+		}
+	}
+	var prefix, suffix string
+	if colorize && len(colors) > 0 {
+		style := termcols.Style{Attrs: colors, Compact: compact}
+		prefix = string(style.Sequence())
+		suffix = string(termcols.Reset)
+	}
+	if _, err := io.WriteString(w, prefix); err != nil {
+		return errPiping
+	}
+	buf := make([]byte, chunkSize)
+	var sim []byte
+	for {
+		n, rErr := r.Read(buf)
+		out := buf[:n]
+		if rw != nil {
+			sim = rw.rewrite(sim[:0], out, false)
+			out = sim
+		}
+		if len(out) > 0 {
+			if _, err := w.Write(out); err != nil {
+				return errPiping
+			}
+			if err := flush(w); err != nil {
+				return errPiping
+			}
+		}
+		if rErr == io.EOF {
+			break
+		}
+		if rErr != nil {
+			if rw != nil {
+				w.Write(rw.rewrite(nil, nil, true))
+			}
+			io.WriteString(w, suffix)
+			flush(w)
 			return errPiping
 		}
-		return nil
 	}
-	_, err = w.Write(text)
-	if err != nil {
+	if rw != nil {
+		if _, err := w.Write(rw.rewrite(nil, nil, true)); err != nil {
+			return errPiping
+		}
+	}
+	if _, err := io.WriteString(w, suffix); err != nil {
 		return errPiping
 	}
+	if err := flush(w); err != nil {
+		return errPiping
+	}
+	return nil
+}
+
+// Flush flushes buffered data to the underlying writer if w buffers data.
+func flush(w io.Writer) error {
+	if f, ok := w.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// PipeAll pipes texts read from files to w one after another in the order of
+// files. Up to prefetchFiles files are read ahead concurrently, so that slow
+// inputs do not hold up the output more than necessary, but at most
+// prefetchChunks chunks are buffered for each of them. Reading stops early
+// when stop is closed.
+func pipeAll(files []io.Reader, w io.Writer, styles []string, colorize bool, stop <-chan struct{}) error {
+	readers := make([]io.Reader, len(files))
+	var started int
+	for i := range files {
+		for ; started < len(files) && started < i+prefetchFiles; started++ {
+			readers[started] = prefetch(files[started], stop)
+		}
+		if err := pipe(readers[i], w, styles, colorize); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// Prefetch starts reading r in a separate goroutine and returns the reader
+// that yields the chunks read from r in order.
+func prefetch(r io.Reader, stop <-chan struct{}) *prefetcher {
+	chunks := make(chan []byte, prefetchChunks)
+	p := &prefetcher{chunks: chunks}
+	go func() {
+		defer close(chunks)
+		for {
+			buf := make([]byte, chunkSize)
+			n, err := r.Read(buf)
+			if n > 0 {
+				select {
+				case chunks <- buf[:n]:
+				case <-stop:
+					return
+				}
+			}
+			if err != nil {
+				if err != io.EOF {
+					p.err = err
+				}
+				return
+			}
+		}
+	}()
+	return p
+}
+
+func (p *prefetcher) Read(b []byte) (int, error) {
+	for len(p.chunk) == 0 {
+		chunk, ok := <-p.chunks
+		if !ok {
+			if p.err != nil {
+				return 0, p.err
+			}
+			return 0, io.EOF
+		}
+		p.chunk = chunk
+	}
+	n := copy(b, p.chunk)
+	p.chunk = p.chunk[n:]
+	return n, nil
+}
+
 func run(args []string, fn openFn) error {
+	if len(args) > 0 && args[0] == paletteCmd {
+		if err := initColorMode(); err != nil {
+			return err
+		}
+		return palette(args[1:], os.Stdout, term.IsTerminal(int(os.Stdout.Fd())))
+	}
+	if len(args) > 0 && args[0] == contrastCmd {
+		if err := initColorMode(); err != nil {
+			return err
+		}
+		return contrast(args[1:], os.Stdout, term.IsTerminal(int(os.Stdout.Fd())))
+	}
+	if len(args) > 0 && args[0] == tableCmd {
+		if err := initColorMode(); err != nil {
+			return err
+		}
+		isTerm := term.IsTerminal(int(os.Stdout.Fd()))
+		var width int
+		if isTerm {
+			width, _, _ = term.GetSize(int(os.Stdout.Fd()))
+		}
+		return renderTable(args[1:], os.Stdin, os.Stdout, isTerm, width)
+	}
+	if len(args) > 0 && args[0] == imageCmd {
+		if err := initColorMode(); err != nil {
+			return err
+		}
+		isTerm := term.IsTerminal(int(os.Stdout.Fd()))
+		var width int
+		if isTerm {
+			width, _, _ = term.GetSize(int(os.Stdout.Fd()))
+		}
+		return renderImage(args[1:], os.Stdout, isTerm, width, termcols.DetectProfile(os.Stdout))
+	}
 	files, closer, err := parse(args, fn)
 	defer closer()
 	if err != nil {
 		return err
 	}
+	if explained {
+		for i, f := range files {
+			files[i] = newExplainReader(f)
+		}
+	}
+	if boxed {
+		for i, f := range files {
+			files[i] = newBoxReader(f, boxBorder)
+		}
+	}
 
 	out := newConcurrentWriter(os.Stdout)
 
-	var colorize bool
-	if term.IsTerminal(int(os.Stdout.Fd())) {
-		colorize = true
-	}
+	colorize := shouldColor(term.IsTerminal(int(os.Stdout.Fd()))) && !explained
 
-	var wg sync.WaitGroup
-	wg.Add(len(files))
+	stop := make(chan struct{})
+	defer close(stop)
 
-	done := make(chan struct{})
-	fail := make(chan error)
-
-	for _, f := range files {
-		go func(r io.Reader) {
-			defer wg.Done()
-			err := pipe(r, out, styles, colorize)
-			if err != nil {
-				fail <- err
-			}
-		}(f)
-	}
+	// NOTE: Input streams such as tail -f never end, so the reset control
+	// sequence has to be written on interrupt not to leave the terminal
+	// styled after the program exits.
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
 
+	done := make(chan error, 1)
 	go func() {
-		wg.Wait()
-		close(done)
+		done <- pipeAll(files, out, styles, colorize, stop)
 	}()
 
 	select {
-	case <-done:
-		break
-	case err := <-fail:
-		return err
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+	case <-sigs:
+		if colorize {
+			io.WriteString(out, string(termcols.Reset))
+		}
+		out.Flush()
+		return errInterrupt
 	}
 
 	if err := out.Flush(); err != nil {
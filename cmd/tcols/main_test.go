@@ -8,17 +8,24 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/mdm-code/termcols"
 )
 
 type (
+	writerFunc func([]byte) (int, error)
 	mockWriter struct{ buff []byte }
 	failWriter struct{}
 	mockReader struct{ text []byte }
 	failReader struct{}
 )
 
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
 func (w *mockWriter) Write(p []byte) (int, error) {
 	w.buff = append(w.buff, p...)
 	return 0, nil
@@ -55,8 +62,8 @@ func TestFail(t *testing.T) {
 		exit, code := f.fail(c.err)
 		defer exit(code)
 
-		if c.w.String() != c.err.Error() {
-			t.Errorf("Have %s; want %s", c.w.String(), c.err.Error())
+		if want := c.err.Error() + "\n"; c.w.String() != want {
+			t.Errorf("Have %s; want %s", c.w.String(), want)
 		}
 	}
 }
@@ -71,6 +78,9 @@ func TestCliParse(t *testing.T) {
 		{"pass-02", []string{"-s", "strike rgb24=fg:242:121:64"}, nil},
 		{"pass-03", []string{"-s", "yellowbg", "--style", "bluefg"}, nil},
 		{"pass-04", []string{}, nil},
+		{"pass-05", []string{"-b", "dark", "-s", "adaptive=bluefg/yellowfg"}, nil},
+		{"pass-06", []string{"--background", "auto"}, nil},
+		{"pass-07", []string{"--compact", "-s", "bold bluefg"}, nil},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -82,6 +92,102 @@ func TestCliParse(t *testing.T) {
 			}
 		})
 	}
+	compact = false
+}
+
+func TestSetBackground(t *testing.T) {
+	cases := []struct {
+		val    string
+		before bool
+		dark   bool
+		err    error
+	}{
+		{"dark", false, true, nil},
+		{"light", true, false, nil},
+		{"DARK", false, true, nil},
+		{"auto", true, true, nil},
+		{"auto", false, false, nil},
+		{"solarized", true, true, errBg},
+	}
+	for _, c := range cases {
+		t.Run(c.val, func(t *testing.T) {
+			// NOTE: The background is reset in each case, so that the auto
+			// case shows that it is left as it is rather than relying on the
+			// state left by the previous case.
+			termcols.SetDarkBackground(c.before)
+			err := setBackground(c.val)
+			if !errors.Is(err, c.err) {
+				t.Errorf("Have %v; want %v", err, c.err)
+			}
+			if dark := termcols.IsDarkBackground(); dark != c.dark {
+				t.Errorf("Have %t; want %t", dark, c.dark)
+			}
+		})
+	}
+}
+
+func TestColorMode(t *testing.T) {
+	cases := []struct {
+		name   string
+		env    string
+		args   []string
+		isTerm bool
+		want   bool
+		err    error
+	}{
+		{"auto-terminal", "", []string{}, true, true, nil},
+		{"auto-pipe", "", []string{}, false, false, nil},
+		{"flag-always", "", []string{"--color", "always"}, false, true, nil},
+		{"flag-never", "", []string{"--color=never"}, true, false, nil},
+		{"flag-auto", "", []string{"--color=AUTO"}, false, false, nil},
+		{"env-always", "always", []string{}, false, true, nil},
+		{"env-never", "never", []string{}, true, false, nil},
+		{"flag-overrides-env", "never", []string{"--color=always"}, false, true, nil},
+		{"env-invalid", "sometimes", []string{}, true, false, errColor},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv(colorEnv, c.env)
+			_, _, err := parse(c.args, open)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Have %v; want %v", err, c.err)
+			}
+			if err != nil {
+				return
+			}
+			if have := shouldColor(c.isTerm); have != c.want {
+				t.Errorf("Have %t; want %t", have, c.want)
+			}
+		})
+	}
+	colorMode = colorAuto
+}
+
+func TestSetColorMode(t *testing.T) {
+	cases := []struct {
+		val  string
+		mode string
+		err  error
+	}{
+		{"always", colorAlways, nil},
+		{"Never", colorNever, nil},
+		{"auto", colorAuto, nil},
+		{"yes", colorAuto, errColor},
+		{"", colorAuto, errColor},
+	}
+	for _, c := range cases {
+		t.Run(c.val, func(t *testing.T) {
+			colorMode = colorAuto
+			err := setColorMode(c.val)
+			if !errors.Is(err, c.err) {
+				t.Errorf("Have %v; want %v", err, c.err)
+			}
+			if colorMode != c.mode {
+				t.Errorf("Have %s; want %s", colorMode, c.mode)
+			}
+		})
+	}
+	colorMode = colorAuto
 }
 
 // TestPipeText tests a single, single-threaded pass of text data.
@@ -95,8 +201,8 @@ func TestPipeText(t *testing.T) {
 	}{
 		{&mockReader{}, &mockWriter{}, []string{}, true, nil},
 		{nil, nil, []string{}, true, errPiping},
-		{&mockReader{}, &mockWriter{}, []string{"blue"}, true, termcols.ErrMap},
-		{&mockReader{}, &mockWriter{}, []string{"red"}, false, termcols.ErrMap},
+		{&mockReader{}, &mockWriter{}, []string{"purple"}, true, termcols.ErrMap},
+		{&mockReader{}, &mockWriter{}, []string{"pink"}, false, termcols.ErrMap},
 		{&failReader{}, &mockWriter{}, []string{}, true, errPiping},
 		{&mockReader{}, &failWriter{}, []string{}, true, errPiping},
 	}
@@ -108,6 +214,151 @@ func TestPipeText(t *testing.T) {
 	}
 }
 
+func TestPipeOutput(t *testing.T) {
+	text := strings.Repeat("Colorize me!\n", 10_000)
+	cases := []struct {
+		name     string
+		reader   io.Reader
+		styles   []string
+		colorize bool
+		want     string
+		err      error
+	}{
+		{
+			"colorized",
+			strings.NewReader(text),
+			[]string{"bold", "bluefg"},
+			true,
+			string(termcols.Bold) + string(termcols.BlueFg) + text + string(termcols.Reset),
+			nil,
+		},
+		{
+			"one-byte-reads",
+			iotest.OneByteReader(strings.NewReader("Colorize me!")),
+			[]string{"redbg"},
+			true,
+			string(termcols.RedBg) + "Colorize me!" + string(termcols.Reset),
+			nil,
+		},
+		{
+			"no-styles",
+			strings.NewReader(text),
+			[]string{},
+			true,
+			text,
+			nil,
+		},
+		{
+			"no-colorize",
+			strings.NewReader(text),
+			[]string{"bold"},
+			false,
+			text,
+			nil,
+		},
+		{
+			"empty-input",
+			strings.NewReader(""),
+			[]string{"italic"},
+			true,
+			string(termcols.Italic) + string(termcols.Reset),
+			nil,
+		},
+		{
+			"failing-read",
+			io.MultiReader(strings.NewReader("Colorize"), &failReader{}),
+			[]string{"italic"},
+			true,
+			string(termcols.Italic) + "Colorize" + string(termcols.Reset),
+			errPiping,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w := &mockWriter{}
+			err := pipe(c.reader, w, c.styles, c.colorize)
+			if !errors.Is(err, c.err) {
+				t.Errorf("Have %v; want %v", err, c.err)
+			}
+			if have := w.String(); have != c.want {
+				t.Errorf("Have %q; want %q", have, c.want)
+			}
+		})
+	}
+}
+
+func TestPipeCompact(t *testing.T) {
+	cases := []struct {
+		name    string
+		styles  []string
+		compact bool
+		want    string
+	}{
+		{
+			"compact",
+			[]string{"bold", "bluefg", "rgb24=bg:1:2:3"},
+			true,
+			"\033[1;34;48;2;1;2;3mColorize me!" + string(termcols.Reset),
+		},
+		{
+			"not-compact",
+			[]string{"bold", "bluefg"},
+			false,
+			string(termcols.Bold) + string(termcols.BlueFg) + "Colorize me!" + string(termcols.Reset),
+		},
+		{
+			"compact-single",
+			[]string{"italic"},
+			true,
+			string(termcols.Italic) + "Colorize me!" + string(termcols.Reset),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			compact = c.compact
+			defer func() { compact = false }()
+			w := &mockWriter{}
+			if err := pipe(strings.NewReader("Colorize me!"), w, c.styles, true); err != nil {
+				t.Fatalf("Have %v; want nil", err)
+			}
+			if have := w.String(); have != c.want {
+				t.Errorf("Have %q; want %q", have, c.want)
+			}
+		})
+	}
+}
+
+// TestPipeStream checks that text gets through before the input ends.
+func TestPipeStream(t *testing.T) {
+	pr, pw := io.Pipe()
+	out := make(chan string)
+	cw := newConcurrentWriter(writerFunc(func(p []byte) (int, error) {
+		out <- string(p)
+		return len(p), nil
+	}))
+	done := make(chan error)
+	go func() {
+		done <- pipe(pr, cw, []string{"bold"}, true)
+	}()
+	for _, line := range []string{"first\n", "second\n"} {
+		pw.Write([]byte(line))
+		want := line
+		if line == "first\n" {
+			want = string(termcols.Bold) + line
+		}
+		if have := <-out; have != want {
+			t.Errorf("Have %q; want %q", have, want)
+		}
+	}
+	pw.Close()
+	if have := <-out; have != string(termcols.Reset) {
+		t.Errorf("Have %q; want %q", have, termcols.Reset)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Have %v; want nil", err)
+	}
+}
+
 func TestOpen(t *testing.T) {
 	errOpen := errors.New("open error")
 	cases := []struct {
@@ -144,6 +395,118 @@ func TestOpen(t *testing.T) {
 	}
 }
 
+func TestOpenStdin(t *testing.T) {
+	var opened []string
+	files, closer, err := open(
+		[]string{"-", "one.txt", "-"},
+		func(fname string) (*os.File, error) {
+			opened = append(opened, fname)
+			return &os.File{}, nil
+		},
+	)
+	closer()
+	if err != nil {
+		t.Fatalf("Have %v; want nil", err)
+	}
+	if !reflect.DeepEqual(opened, []string{"one.txt"}) {
+		t.Errorf("Have %v; want [one.txt]", opened)
+	}
+	if len(files) != 3 || files[0] != io.Reader(os.Stdin) || files[2] == io.Reader(os.Stdin) {
+		t.Errorf("Have %v; want stdin read once", files)
+	}
+	if _, err := os.Stdin.Stat(); err != nil {
+		t.Errorf("Have %v; want stdin left open", err)
+	}
+}
+
+// slowReader delays the first read to have readers complete out of order.
+type slowReader struct {
+	io.Reader
+	delay time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(r.delay)
+	r.delay = 0
+	return r.Reader.Read(p)
+}
+
+func TestPipeAll(t *testing.T) {
+	big := strings.Repeat("b", 10*chunkSize+7)
+	cases := []struct {
+		name     string
+		files    []io.Reader
+		styles   []string
+		colorize bool
+		want     string
+		err      error
+	}{
+		{
+			"ordered",
+			[]io.Reader{
+				&slowReader{strings.NewReader("first\n"), 30 * time.Millisecond},
+				&slowReader{strings.NewReader("second\n"), 10 * time.Millisecond},
+				strings.NewReader("third\n"),
+			},
+			[]string{},
+			true,
+			"first\nsecond\nthird\n",
+			nil,
+		},
+		{
+			"large-files",
+			[]io.Reader{
+				iotest.HalfReader(strings.NewReader(big)),
+				strings.NewReader("a"),
+				strings.NewReader(big),
+			},
+			[]string{},
+			false,
+			big + "a" + big,
+			nil,
+		},
+		{
+			"more-files-than-prefetched",
+			[]io.Reader{
+				strings.NewReader("1"), strings.NewReader("2"),
+				strings.NewReader("3"), strings.NewReader("4"),
+				strings.NewReader("5"), strings.NewReader("6"),
+			},
+			[]string{"bold"},
+			true,
+			"\033[1m1\033[0m\033[1m2\033[0m\033[1m3\033[0m" +
+				"\033[1m4\033[0m\033[1m5\033[0m\033[1m6\033[0m",
+			nil,
+		},
+		{
+			"failing-file",
+			[]io.Reader{
+				strings.NewReader("first"),
+				io.MultiReader(strings.NewReader("second"), &failReader{}),
+				strings.NewReader("third"),
+			},
+			[]string{},
+			true,
+			"firstsecond",
+			errPiping,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stop := make(chan struct{})
+			defer close(stop)
+			w := &mockWriter{}
+			err := pipeAll(c.files, w, c.styles, c.colorize, stop)
+			if !errors.Is(err, c.err) {
+				t.Errorf("Have %v; want %v", err, c.err)
+			}
+			if have := w.String(); have != c.want {
+				t.Errorf("Have %q; want %q", have, c.want)
+			}
+		})
+	}
+}
+
 func TestPrepUsageAttrs(t *testing.T) {
 	cases := []struct {
 		name    string
@@ -182,6 +545,9 @@ func TestRun(t *testing.T) {
 	}{
 		{"pass-01", []string{"-s", "greenbg yellowfg bold", "1.pyc", "2.c"}, f, nil},
 		{"pass-02", []string{}, f, nil},
+		{"palette", []string{"palette", "--plain"}, f, nil},
+		{"contrast", []string{"contrast", "#777", "white"}, f, nil},
+		{"contrast-fail", []string{"contrast", "#777"}, f, errContrastArgs},
 		{"fail-01", []string{"--style", "wacky", "hello.py"}, f, termcols.ErrMap},
 	}
 	for _, c := range cases {
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mdm-code/termcols"
+)
+
+const (
+	paletteCmd   = "palette"
+	stripWidth   = 72
+	grayRowCells = 12
+)
+
+var (
+	errLayer     error = errors.New("layer must be one of fg or bg")
+	paletteUsage       = `tcols palette - show colors supported by the terminal
+
+Palette prints the 16 standard and bright ANSI colors, the 6x6x6 color cube
+and the grayscale ramp of the 256-color palette along with their indices that
+can be used with the rgb8 style. It closes with truecolor strips to check
+whether 24-bit colors used with the rgb24 style render smoothly.
+
+Usage:
+	tcols palette [-l|--layer fg|bg] [-p|--plain] [--color auto|always|never]
+
+Options:
+	-h, --help   show this help message and exit
+	-l, --layer  apply colors to either foreground or background (default bg)
+	-p, --plain  print color indices only without any color
+	    --color  when to colorize text: auto, always or never
+`
+)
+
+// PaletteOpts holds options of the palette command.
+type paletteOpts struct {
+	layer termcols.Layer
+	plain bool
+}
+
+// ParsePalette parses command-line arguments of the palette command.
+func parsePalette(args []string) (paletteOpts, error) {
+	opts := paletteOpts{layer: termcols.BG}
+	fs := flag.NewFlagSet("tcols palette", flag.ExitOnError)
+	for _, fName := range []string{"l", "layer"} {
+		fs.Func(
+			fName,
+			"apply colors to either foreground or background",
+			func(v string) error {
+				switch strings.ToLower(v) {
+				case "fg":
+					opts.layer = termcols.FG
+				case "bg":
+					opts.layer = termcols.BG
+				default:
+					return errLayer
+				}
+				return nil
+			},
+		)
+	}
+	for _, fName := range []string{"p", "plain"} {
+		fs.BoolVar(&opts.plain, fName, false, "print color indices only without any color")
+	}
+	colorFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), paletteUsage)
+	}
+	err := fs.Parse(args)
+	return opts, err
+}
+
+// Palette writes the 256-color palette followed by truecolor strips to w.
+// Colors are left out when the color mode and whether w isTerm terminal call
+// for it or when the plain option is set.
+func palette(args []string, w io.Writer, isTerm bool) error {
+	opts, err := parsePalette(args)
+	if err != nil {
+		return err
+	}
+	colorize := shouldColor(isTerm) && !opts.plain
+	var b strings.Builder
+	b.WriteString("Standard colors:\n")
+	writeCells(&b, 0, 8, opts.layer, colorize)
+	b.WriteString("\nBright colors:\n")
+	writeCells(&b, 8, 16, opts.layer, colorize)
+	b.WriteString("\nColor cube:\n")
+	for _, rs := range [][2]int{{0, 3}, {3, 6}} {
+		for g := 0; g < 6; g++ {
+			for r := rs[0]; r < rs[1]; r++ {
+				if r > rs[0] {
+					b.WriteString("  ")
+				}
+				start := 16 + 36*r + 6*g
+				writeCells(&b, start, start+6, opts.layer, colorize)
+			}
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("Grayscale ramp:\n")
+	for start := 232; start < 256; start += grayRowCells {
+		writeCells(&b, start, start+grayRowCells, opts.layer, colorize)
+		b.WriteString("\n")
+	}
+	if colorize {
+		b.WriteString("Truecolor:\n")
+		writeStrip(&b, hueAt, opts.layer)
+		writeStrip(&b, grayAt, opts.layer)
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return errPiping
+	}
+	return nil
+}
+
+// WriteCells writes palette entries in the range [start, end) next to each
+// other.
+func writeCells(b *strings.Builder, start, end int, l termcols.Layer, colorize bool) {
+	for i := start; i < end; i++ {
+		cell := fmt.Sprintf(" %3d", i)
+		if !colorize {
+			b.WriteString(cell)
+			continue
+		}
+		attrs := []termcols.SgrAttr{termcols.Rgb8(l, uint8(i))}
+		if l == termcols.BG {
+			attrs = append(attrs, contrastFg(uint8(i)))
+		}
+		b.WriteString(termcols.Colorize(cell, attrs...))
+	}
+}
+
+// WriteStrip writes a line of stripWidth cells colored with 24-bit colors
+// returned by the color function for positions in the range [0, 1].
+func writeStrip(b *strings.Builder, color func(float64) termcols.Color, l termcols.Layer) {
+	cell := " "
+	if l == termcols.FG {
+		cell = "█"
+	}
+	for i := 0; i < stripWidth; i++ {
+		c := color(float64(i) / float64(stripWidth-1))
+		b.WriteString(string(termcols.Rgb24(l, c.R, c.G, c.B)))
+		b.WriteString(cell)
+	}
+	b.WriteString(string(termcols.Reset))
+	b.WriteString("\n")
+}
+
+// HueAt returns a fully saturated color at position t of the hue circle.
+func hueAt(t float64) termcols.Color {
+	return termcols.HSL(360*t, 1, 0.5)
+}
+
+// GrayAt returns a shade of gray at position t between black and white.
+func grayAt(t float64) termcols.Color {
+	v := uint8(255*t + 0.5)
+	return termcols.Color{R: v, G: v, B: v}
+}
+
+// ContrastFg picks either black or white foreground for the palette entry i
+// used as the background depending on how light the entry is.
+func contrastFg(i uint8) termcols.SgrAttr {
+	c := termcols.PaletteColor(i)
+	// NOTE: ITU-R BT.601 luma is good enough to tell light colors apart.
+	if 299*int(c.R)+587*int(c.G)+114*int(c.B) > 128_000 {
+		return termcols.Rgb8(termcols.FG, 16)
+	}
+	return termcols.Rgb8(termcols.FG, 231)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mdm-code/termcols"
+)
+
+func TestParsePalette(t *testing.T) {
+	cases := []struct {
+		name  string
+		args  []string
+		layer termcols.Layer
+		plain bool
+	}{
+		{"defaults", []string{}, termcols.BG, false},
+		{"layer-fg", []string{"-l", "fg"}, termcols.FG, false},
+		{"layer-bg", []string{"--layer", "BG"}, termcols.BG, false},
+		{"plain", []string{"-p"}, termcols.BG, true},
+		{"all", []string{"--plain", "--layer", "fg"}, termcols.FG, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts, err := parsePalette(c.args)
+			if err != nil {
+				t.Fatalf("Have %v; want nil", err)
+			}
+			if opts.layer != c.layer || opts.plain != c.plain {
+				t.Errorf("Have %+v; want {layer:%s plain:%t}", opts, c.layer, c.plain)
+			}
+		})
+	}
+}
+
+func TestPalette(t *testing.T) {
+	cases := []struct {
+		name     string
+		args     []string
+		isTerm   bool
+		contains []string
+		excludes []string
+	}{
+		{
+			"plain",
+			[]string{"--plain"},
+			true,
+			[]string{"   0   1", " 231\n", " 255\n"},
+			[]string{termcols.Esc, "Truecolor"},
+		},
+		{
+			"not-terminal",
+			[]string{},
+			false,
+			[]string{" 16  17"},
+			[]string{termcols.Esc},
+		},
+		{
+			"background",
+			[]string{},
+			true,
+			[]string{
+				string(termcols.Rgb8(termcols.BG, 0)) + string(termcols.Rgb8(termcols.FG, 231)) + "   0",
+				string(termcols.Rgb8(termcols.BG, 231)) + string(termcols.Rgb8(termcols.FG, 16)) + " 231",
+				"Truecolor",
+				string(termcols.Rgb24(termcols.BG, 255, 0, 0)) + " ",
+			},
+			[]string{},
+		},
+		{
+			"color-never",
+			[]string{"--color", "never"},
+			true,
+			[]string{" 16  17"},
+			[]string{termcols.Esc},
+		},
+		{
+			"color-always",
+			[]string{"--color", "always", "-l", "fg"},
+			false,
+			[]string{string(termcols.Rgb8(termcols.FG, 16)) + "  16"},
+			[]string{},
+		},
+		{
+			"foreground",
+			[]string{"-l", "fg"},
+			true,
+			[]string{
+				string(termcols.Rgb8(termcols.FG, 9)) + "   9" + string(termcols.Reset),
+				string(termcols.Rgb24(termcols.FG, 255, 255, 255)) + "█",
+			},
+			[]string{string(termcols.Rgb8(termcols.BG, 9))},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			colorMode = colorAuto
+			w := &mockWriter{}
+			if err := palette(c.args, w, c.isTerm); err != nil {
+				t.Fatalf("Have %v; want nil", err)
+			}
+			out := w.String()
+			for _, s := range c.contains {
+				if !strings.Contains(out, s) {
+					t.Errorf("Output misses %q", s)
+				}
+			}
+			for _, s := range c.excludes {
+				if strings.Contains(out, s) {
+					t.Errorf("Output contains %q", s)
+				}
+			}
+		})
+	}
+	colorMode = colorAuto
+}
+
+func TestPaletteFail(t *testing.T) {
+	if err := palette([]string{}, &failWriter{}, true); err != errPiping {
+		t.Errorf("Have %v; want %v", err, errPiping)
+	}
+}
+
+func TestHueAt(t *testing.T) {
+	cases := []struct {
+		pos     float64
+		r, g, b uint8
+	}{
+		{0, 255, 0, 0},
+		{1.0 / 6, 255, 255, 0},
+		{0.25, 128, 255, 0},
+		{0.5, 0, 255, 255},
+		{1, 255, 0, 0},
+	}
+	for _, c := range cases {
+		t.Run("", func(t *testing.T) {
+			out := hueAt(c.pos)
+			if exp := (termcols.Color{R: c.r, G: c.g, B: c.b}); out != exp {
+				t.Errorf("Have %v; want %v", out, exp)
+			}
+		})
+	}
+}
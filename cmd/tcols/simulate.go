@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/mdm-code/termcols"
+)
+
+var (
+	errSimulate error = errors.New("simulate must be one of protanopia, deuteranopia, tritanopia or achromatopsia")
+	simulation  termcols.Deficiency
+)
+
+// Rewriter rewrites colors of SGR control sequences found in a stream of text
+// the way they are seen by people with the color vision deficiency d.
+type rewriter struct {
+	d     termcols.Deficiency
+	carry []byte
+}
+
+// SetSimulation sets the color vision deficiency to simulate.
+func setSimulation(v string) error {
+	for _, d := range []termcols.Deficiency{
+		termcols.Protanopia,
+		termcols.Deuteranopia,
+		termcols.Tritanopia,
+		termcols.Achromatopsia,
+	} {
+		if strings.EqualFold(v, d.String()) {
+			simulation = d
+			return nil
+		}
+	}
+	return errSimulate
+}
+
+// Rewrite appends the chunk of text with colors rewritten to dst. The text is
+// split with termcols.ScanSequences, and a control sequence cut off at the
+// end of the chunk is held back until the next call, or written out as it is
+// when atEOF is set.
+func (rw *rewriter) rewrite(dst, chunk []byte, atEOF bool) []byte {
+	data := chunk
+	if len(rw.carry) > 0 {
+		data = append(rw.carry, chunk...)
+	}
+	for len(data) > 0 {
+		n, tok, _ := termcols.ScanSequences(data, atEOF)
+		if n == 0 {
+			break
+		}
+		if bytes.HasPrefix(tok, []byte(termcols.Csi)) && tok[len(tok)-1] == 'm' {
+			dst = append(dst, termcols.SimulateAttr(termcols.SgrAttr(tok), rw.d)...)
+		} else {
+			dst = append(dst, tok...)
+		}
+		data = data[n:]
+	}
+	rw.carry = append(rw.carry[:0], data...)
+	return dst
+}
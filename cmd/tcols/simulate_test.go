@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/mdm-code/termcols"
+)
+
+func TestSetSimulation(t *testing.T) {
+	cases := []struct {
+		v   string
+		exp termcols.Deficiency
+		err error
+	}{
+		{"protanopia", termcols.Protanopia, nil},
+		{"Deuteranopia", termcols.Deuteranopia, nil},
+		{"TRITANOPIA", termcols.Tritanopia, nil},
+		{"achromatopsia", termcols.Achromatopsia, nil},
+		{"colorblind", 0, errSimulate},
+		{"", 0, errSimulate},
+	}
+	for _, c := range cases {
+		t.Run(c.v, func(t *testing.T) {
+			simulation = 0
+			defer func() { simulation = 0 }()
+			if err := setSimulation(c.v); err != c.err || simulation != c.exp {
+				t.Errorf("Have %v %v; want %v %v", simulation, err, c.exp, c.err)
+			}
+		})
+	}
+}
+
+func TestRewriter(t *testing.T) {
+	d := termcols.Achromatopsia
+	red := string(termcols.SimulateAttr(termcols.RedFg, d))
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "no colors here", "no colors here"},
+		{"color", "a" + string(termcols.RedFg) + "b", "a" + red + "b"},
+		{"other", string(termcols.Bold) + "b" + string(termcols.Reset), string(termcols.Bold) + "b" + string(termcols.Reset)},
+		{"cursor", "\033[2Ka\033[31m", "\033[2Ka" + red},
+		{"unterminated", "a\033[31", "a\033[31"},
+		{"lone-esc", "a\033", "a\033"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// NOTE: Feeding the input one byte at a time cuts every control
+			// sequence between chunks.
+			for _, size := range []int{len(c.in), 1} {
+				rw := &rewriter{d: d}
+				var out []byte
+				for i := 0; i < len(c.in); i += size {
+					out = rw.rewrite(out, []byte(c.in[i:min(i+size, len(c.in))]), false)
+				}
+				out = rw.rewrite(out, nil, true)
+				if have := string(out); have != c.want {
+					t.Errorf("Have %q; want %q", have, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPipeSimulate(t *testing.T) {
+	d := termcols.Deuteranopia
+	simulation = d
+	defer func() { simulation = 0 }()
+	in := "a" + string(termcols.GreenFg) + "b"
+	w := &mockWriter{}
+	r := iotest.OneByteReader(strings.NewReader(in))
+	if err := pipe(r, w, []string{"redfg"}, true); err != nil {
+		t.Fatalf("Have %v; want nil", err)
+	}
+	want := string(termcols.SimulateAttr(termcols.RedFg, d)) + "a" +
+		string(termcols.SimulateAttr(termcols.GreenFg, d)) + "b" + string(termcols.Reset)
+	if have := w.String(); have != want {
+		t.Errorf("Have %q; want %q", have, want)
+	}
+}
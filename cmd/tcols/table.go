@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mdm-code/termcols"
+	"github.com/mdm-code/termcols/table"
+)
+
+const tableCmd = "table"
+
+var (
+	errBorder     error = errors.New("border must be one of light, ascii, rounded, heavy, double, markdown or none")
+	errAlign      error = errors.New("align must be a comma-separated list of left, right or center")
+	errDelimiter  error = errors.New("delimiter must be auto, comma, tab or a single character")
+	errTableInput error = errors.New("malformed table input")
+	tableUsage          = `tcols table - render CSV or TSV as a table
+
+Table reads comma-separated or tab-separated values from the standard input
+and writes them out as a table with aligned columns. The first record is used
+as the header unless the --no-header flag is given. When the output is a
+terminal, columns are shrunk to fit its width, and cells that do not fit are
+truncated.
+
+Styles given to --header and --zebra use the same syntax as the --style flag
+of tcols. The zebra style is applied to every other row.
+
+Usage:
+	tcols table [-d|--delimiter auto|comma|tab|char] [--no-header]
+	            [--border light|ascii|rounded|heavy|double|markdown|none]
+	            [-a|--align list] [--header style] [--zebra style]
+	            [-w|--width n] [--color auto|always|never]
+
+Options:
+	-h, --help       show this help message and exit
+	-d, --delimiter  field delimiter, detected from the first line by default
+	    --no-header  treat the first record as a regular row
+	    --border     border style of the table (default light)
+	-a, --align      comma-separated alignments of columns: left, right, center
+	    --header     style of the header (default bold)
+	    --zebra      style of every other row
+	-w, --width      maximum width of the table, 0 for the terminal width
+	    --color      when to colorize text: auto, always or never
+
+Example:
+	tcols table --align left,right --zebra 'on grey3' < data.csv
+`
+	tableBorders = map[string]table.Border{
+		"light":    table.Light,
+		"ascii":    table.ASCII,
+		"rounded":  table.Rounded,
+		"heavy":    table.Heavy,
+		"double":   table.Double,
+		"markdown": table.Markdown,
+		"none":     table.None,
+	}
+	tableAligns = map[string]table.Align{
+		"left":   table.Left,
+		"l":      table.Left,
+		"right":  table.Right,
+		"r":      table.Right,
+		"center": table.Center,
+		"c":      table.Center,
+	}
+)
+
+// TableOpts holds options of the table command.
+type tableOpts struct {
+	delimiter rune
+	noHeader  bool
+	border    table.Border
+	align     []table.Align
+	header    string
+	zebra     string
+	width     int
+}
+
+// ParseTable parses command-line arguments of the table command.
+func parseTable(args []string) (tableOpts, error) {
+	opts := tableOpts{border: table.Light, header: "bold"}
+	fs := flag.NewFlagSet("tcols table", flag.ExitOnError)
+	for _, fName := range []string{"d", "delimiter"} {
+		fs.Func(fName, "field delimiter", func(v string) error {
+			switch strings.ToLower(v) {
+			case "auto":
+				opts.delimiter = 0
+			case "comma":
+				opts.delimiter = ','
+			case "tab":
+				opts.delimiter = '\t'
+			default:
+				r, n := utf8.DecodeRuneInString(v)
+				if n == 0 || n != len(v) || r == '"' || r == '\n' || r == '\r' {
+					return errDelimiter
+				}
+				opts.delimiter = r
+			}
+			return nil
+		})
+	}
+	fs.BoolVar(&opts.noHeader, "no-header", false, "treat the first record as a regular row")
+	fs.Func("border", "border style of the table", func(v string) error {
+		b, ok := tableBorders[strings.ToLower(v)]
+		if !ok {
+			return errBorder
+		}
+		opts.border = b
+		return nil
+	})
+	for _, fName := range []string{"a", "align"} {
+		fs.Func(fName, "comma-separated alignments of columns", func(v string) error {
+			opts.align = opts.align[:0]
+			for _, f := range strings.Split(v, ",") {
+				a, ok := tableAligns[strings.ToLower(strings.TrimSpace(f))]
+				if !ok {
+					return errAlign
+				}
+				opts.align = append(opts.align, a)
+			}
+			return nil
+		})
+	}
+	fs.StringVar(&opts.header, "header", opts.header, "style of the header")
+	fs.StringVar(&opts.zebra, "zebra", "", "style of every other row")
+	for _, fName := range []string{"w", "width"} {
+		fs.IntVar(&opts.width, fName, 0, "maximum width of the table")
+	}
+	colorFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), tableUsage)
+	}
+	err := fs.Parse(args)
+	return opts, err
+}
+
+// RenderTable reads records from r and writes them out to w as a table.
+// Styles are applied when the color mode and whether w isTerm terminal call
+// for it. The table is shrunk to termWidth unless the width option is set,
+// and a termWidth of 0 stands for no limit.
+func renderTable(args []string, r io.Reader, w io.Writer, isTerm bool, termWidth int) error {
+	opts, err := parseTable(args)
+	if err != nil {
+		return err
+	}
+	records, err := readRecords(r, opts.delimiter)
+	if err != nil {
+		return err
+	}
+	var t *table.Table
+	if len(records) > 0 && !opts.noHeader {
+		t, records = table.New(records[0]...), records[1:]
+	} else {
+		t = table.New()
+	}
+	for _, rec := range records {
+		t.AddRow(rec...)
+	}
+	t.SetBorder(opts.border)
+	for i, a := range opts.align {
+		t.SetAlign(i, a)
+	}
+	if shouldColor(isTerm) {
+		header, err := mapStyle(opts.header)
+		if err != nil {
+			return err
+		}
+		zebra, err := mapStyle(opts.zebra)
+		if err != nil {
+			return err
+		}
+		t.SetHeaderStyle(header...)
+		t.SetZebra(zebra...)
+	}
+	if opts.width > 0 {
+		t.SetMaxWidth(opts.width)
+	} else {
+		t.SetMaxWidth(termWidth)
+	}
+	if _, err := t.WriteTo(w); err != nil {
+		return errPiping
+	}
+	return nil
+}
+
+// MapStyle maps the style s onto SGR attributes. An empty style maps onto
+// no attributes.
+func mapStyle(s string) ([]termcols.SgrAttr, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	return termcols.MapColors([]string{s})
+}
+
+// ReadRecords reads all records from r separated with the delimiter. A zero
+// delimiter is detected from the first line: tab when it contains one and
+// comma otherwise.
+func readRecords(r io.Reader, delimiter rune) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errPiping
+	}
+	if delimiter == 0 {
+		line, _, _ := strings.Cut(string(data), "\n")
+		delimiter = ','
+		if strings.ContainsRune(line, '\t') {
+			delimiter = '\t'
+		}
+	}
+	cr := csv.NewReader(strings.NewReader(string(data)))
+	cr.Comma = delimiter
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = delimiter == '\t'
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errTableInput, err)
+	}
+	return records, nil
+}
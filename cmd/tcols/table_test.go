@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mdm-code/termcols"
+	"github.com/mdm-code/termcols/table"
+)
+
+func TestParseTable(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		exp  tableOpts
+	}{
+		{"defaults", []string{}, tableOpts{border: table.Light, header: "bold"}},
+		{"delimiter-tab", []string{"-d", "tab"}, tableOpts{delimiter: '\t', border: table.Light, header: "bold"}},
+		{"delimiter-char", []string{"--delimiter", ";"}, tableOpts{delimiter: ';', border: table.Light, header: "bold"}},
+		{"border", []string{"--border", "ASCII"}, tableOpts{border: table.ASCII, header: "bold"}},
+		{"align", []string{"-a", "left, r,center"}, tableOpts{border: table.Light, header: "bold", align: []table.Align{table.Left, table.Right, table.Center}}},
+		{"styles", []string{"--header", "", "--zebra", "on grey3", "-w", "40", "--no-header"}, tableOpts{border: table.Light, zebra: "on grey3", width: 40, noHeader: true}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			opts, err := parseTable(c.args)
+			if err != nil {
+				t.Fatalf("Have %v; want nil", err)
+			}
+			if !reflect.DeepEqual(opts, c.exp) {
+				t.Errorf("Have %+v; want %+v", opts, c.exp)
+			}
+		})
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	cases := []struct {
+		name      string
+		args      []string
+		input     string
+		isTerm    bool
+		termWidth int
+		want      string
+		err       error
+	}{
+		{
+			"csv",
+			[]string{"--border", "markdown"},
+			"name,n\nfoo,1\n",
+			false,
+			0,
+			"| name | n |\n|------|---|\n| foo  | 1 |\n",
+			nil,
+		},
+		{
+			"tsv",
+			[]string{"--border", "markdown", "--no-header", "-a", "l,r"},
+			"a\t10\nbb\t2\n",
+			false,
+			0,
+			"| a  | 10 |\n| bb |  2 |\n",
+			nil,
+		},
+		{
+			"quoted",
+			[]string{"--border", "none"},
+			"\"a,b\",c\n",
+			false,
+			0,
+			"a,b  c\n",
+			nil,
+		},
+		{
+			"term-width",
+			[]string{"--border", "markdown", "--color", "never"},
+			"description\na long story\n",
+			true,
+			12,
+			"| descrip… |\n|----------|\n| a long … |\n",
+			nil,
+		},
+		{
+			"width-flag",
+			[]string{"--border", "markdown", "-w", "9", "--color", "never"},
+			"description\n",
+			true,
+			80,
+			"| desc… |\n",
+			nil,
+		},
+		{
+			"styled",
+			[]string{"--border", "none", "--header", "bold", "--zebra", "on blue", "--color", "always"},
+			"h\nx\ny\n",
+			false,
+			0,
+			termcols.Colorize("h", termcols.Bold) + "\nx\n" + termcols.Colorize("y", termcols.BlueBg) + "\n",
+			nil,
+		},
+		{
+			"bad-style",
+			[]string{"--zebra", "on purple", "--color", "always"},
+			"h\n",
+			false,
+			0,
+			"",
+			termcols.ErrMap,
+		},
+		{
+			"malformed",
+			[]string{},
+			"a,\"b\n",
+			false,
+			0,
+			"",
+			errTableInput,
+		},
+		{
+			"empty",
+			[]string{},
+			"",
+			false,
+			0,
+			"",
+			nil,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			defer func() { colorMode = colorAuto }()
+			var b strings.Builder
+			err := renderTable(c.args, strings.NewReader(c.input), &b, c.isTerm, c.termWidth)
+			if !errors.Is(err, c.err) {
+				t.Fatalf("Have %v; want %v", err, c.err)
+			}
+			if have := b.String(); have != c.want {
+				t.Errorf("Have %q; want %q", have, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+package termcols
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrColor indicates that a string could not be parsed as a color.
+	ErrColor = errors.New("Color parsing error")
+)
+
+// Color represents a 24-bit RGB color with each one of the three channels
+// taking a value in the range [0, 255].
+//
+// Besides RGB, colors can be converted to and from hex strings, HSL, OKLab
+// and the 256-color palette. Methods that manipulate colors return new
+// values, so Color can be passed around and compared freely.
+type Color struct {
+	R, G, B uint8
+}
+
+// ParseHex parses the hex color s of the #rgb or #rrggbb form. The leading #
+// is optional and hex digits are case-insensitive. It returns ErrColor when s
+// is not a valid hex color.
+func ParseHex(s string) (Color, error) {
+	s = strings.TrimPrefix(s, "#")
+	var c [3]uint8
+	switch len(s) {
+	case 3:
+		for i := range c {
+			v, ok := hexDigit(s[i])
+			if !ok {
+				return Color{}, ErrColor
+			}
+			c[i] = v<<4 | v
+		}
+	case 6:
+		for i := range c {
+			hi, ok := hexDigit(s[2*i])
+			if !ok {
+				return Color{}, ErrColor
+			}
+			lo, ok := hexDigit(s[2*i+1])
+			if !ok {
+				return Color{}, ErrColor
+			}
+			c[i] = hi<<4 | lo
+		}
+	default:
+		return Color{}, ErrColor
+	}
+	return Color{c[0], c[1], c[2]}, nil
+}
+
+// ParseColor parses the color s written the way a single color is written in
+// styles passed to [MapColor], such as red, bright-blue, grey23, #ff8700, 208,
+// bg=cyan, bluefg or rgb24=fg:1:2:3. The layer the color applies to is
+// ignored. The 16 ANSI colors are approximated with their xterm defaults, and
+// adaptive colors are resolved with [IsDarkBackground]. It returns an error
+// matching ErrColor when s is not a single color.
+func ParseColor(s string) (Color, error) {
+	a, err := MapColor(s)
+	if err != nil {
+		return Color{}, fmt.Errorf("%w: %w", ErrColor, err)
+	}
+	params, ok := a.Params()
+	if !ok {
+		return Color{}, ErrColor
+	}
+	_, c, n, ok := paramColor(params)
+	if !ok || n != len(params) {
+		return Color{}, ErrColor
+	}
+	return c, nil
+}
+
+// HSL returns the color with the hue h given in degrees, and the saturation s
+// and the lightness l in the range [0, 1]. The hue wraps around the circle,
+// while s and l are clamped.
+func HSL(h, s, l float64) Color {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	s, l = clamp01(s), clamp01(l)
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return Color{channel(r + m), channel(g + m), channel(b + m)}
+}
+
+// OKLab returns the color with the perceptual lightness l in the range
+// [0, 1] and the a and b opponent axes of the OKLab color space. Colors
+// outside of the sRGB gamut are clipped.
+func OKLab(l, a, b float64) Color {
+	l_ := l + 0.3963377774*a + 0.2158037573*b
+	m_ := l - 0.1055613458*a - 0.0638541728*b
+	s_ := l - 0.0894841775*a - 1.2914855480*b
+	lc, mc, sc := l_*l_*l_, m_*m_*m_, s_*s_*s_
+	return Color{
+		delinearize(+4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc),
+		delinearize(-1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc),
+		delinearize(-0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc),
+	}
+}
+
+// PaletteColor returns the color of the entry i of the 256-color palette. The
+// 16 ANSI colors depend on the terminal theme, so they are approximated with
+// the xterm defaults.
+func PaletteColor(i uint8) Color {
+	switch {
+	case i < 16:
+		return ansiColors[i]
+	case i < 232:
+		n := i - 16
+		return Color{cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]}
+	default:
+		v := 8 + 10*(i-232)
+		return Color{v, v, v}
+	}
+}
+
+// Hex returns the color as a lowercase hex string of the #rrggbb form.
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// HSL returns the hue of the color c in degrees in the range [0, 360), and
+// its saturation and lightness in the range [0, 1].
+func (c Color) HSL() (h, s, l float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l = (max + min) / 2
+	d := max - min
+	if d == 0 {
+		return 0, 0, l
+	}
+	s = d / (1 - math.Abs(2*l-1))
+	switch max {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, l
+}
+
+// OKLab returns the perceptual lightness l and the a and b opponent axes of
+// the color c in the OKLab color space.
+func (c Color) OKLab() (l, a, b float64) {
+	r, g, bl := linearize(c.R), linearize(c.G), linearize(c.B)
+	lc := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*bl)
+	mc := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*bl)
+	sc := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*bl)
+	l = 0.2104542553*lc + 0.7936177850*mc - 0.0040720468*sc
+	a = 1.9779984951*lc - 2.4285922050*mc + 0.4505937099*sc
+	b = 0.0259040371*lc + 0.7827717662*mc - 0.8086757660*sc
+	return l, a, b
+}
+
+// PaletteIndex returns the index of the entry of the 256-color palette closest
+// to the color c as measured by Distance. Only the color cube and the
+// grayscale ramp are considered, since the 16 ANSI colors vary between
+// terminal themes.
+func (c Color) PaletteIndex() uint8 {
+	return c.nearest(16, len(paletteLab))
+}
+
+// Lighten returns the color c with its HSL lightness increased by amount,
+// where 1 turns any color white.
+func (c Color) Lighten(amount float64) Color {
+	h, s, l := c.HSL()
+	return HSL(h, s, l+amount)
+}
+
+// Darken returns the color c with its HSL lightness decreased by amount,
+// where 1 turns any color black.
+func (c Color) Darken(amount float64) Color {
+	return c.Lighten(-amount)
+}
+
+// Saturate returns the color c with its HSL saturation increased by amount.
+// Negative amounts desaturate the color, and -1 turns it gray.
+func (c Color) Saturate(amount float64) Color {
+	h, s, l := c.HSL()
+	return HSL(h, s+amount, l)
+}
+
+// Complement returns the color on the opposite side of the hue circle.
+func (c Color) Complement() Color {
+	h, s, l := c.HSL()
+	return HSL(h+180, s, l)
+}
+
+// Mix blends the color c with the color d, where t set to 0 yields c and t
+// set to 1 yields d. Colors are interpolated in OKLab, so that the blend
+// changes evenly to the eye. The t parameter is clamped to the range [0, 1].
+func (c Color) Mix(d Color, t float64) Color {
+	t = clamp01(t)
+	l1, a1, b1 := c.OKLab()
+	l2, a2, b2 := d.OKLab()
+	return OKLab(l1+(l2-l1)*t, a1+(a2-a1)*t, b1+(b2-b1)*t)
+}
+
+// Distance returns the perceptual difference between the colors c and d as
+// the Euclidean distance in OKLab. It is 0 for equal colors and about 1 for
+// black and white.
+func (c Color) Distance(d Color) float64 {
+	l1, a1, b1 := c.OKLab()
+	l2, a2, b2 := d.OKLab()
+	return labDistance(l1, a1, b1, l2, a2, b2)
+}
+
+// Fg returns the 24-bit foreground color control sequence of the color c.
+func (c Color) Fg() SgrAttr {
+	return Rgb24(FG, c.R, c.G, c.B)
+}
+
+// Bg returns the 24-bit background color control sequence of the color c.
+func (c Color) Bg() SgrAttr {
+	return Rgb24(BG, c.R, c.G, c.B)
+}
+
+// Fg8 returns the 8-bit foreground color control sequence of the palette
+// entry closest to the color c for terminals without 24-bit color support.
+func (c Color) Fg8() SgrAttr {
+	return Rgb8(FG, c.PaletteIndex())
+}
+
+// Bg8 returns the 8-bit background color control sequence of the palette
+// entry closest to the color c for terminals without 24-bit color support.
+func (c Color) Bg8() SgrAttr {
+	return Rgb8(BG, c.PaletteIndex())
+}
+
+// AnsiColors holds xterm defaults of the 16 ANSI colors.
+var ansiColors = [16]Color{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// PaletteLab caches OKLab coordinates of the 256-color palette entries used
+// to look up the closest entry.
+var (
+	paletteLab     [256][3]float64
+	paletteLabOnce sync.Once
+)
+
+// Nearest returns the index of the palette entry in the range [lo, hi) closest
+// to the color c.
+func (c Color) nearest(lo, hi int) uint8 {
+	paletteLabOnce.Do(func() {
+		for i := range paletteLab {
+			l, a, b := PaletteColor(uint8(i)).OKLab()
+			paletteLab[i] = [3]float64{l, a, b}
+		}
+	})
+	l, a, b := c.OKLab()
+	best, bestDist := lo, math.Inf(1)
+	for i := lo; i < hi; i++ {
+		p := paletteLab[i]
+		if d := labDistance(l, a, b, p[0], p[1], p[2]); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return uint8(best)
+}
+
+// CubeLevels holds channel values of the 6x6x6 color cube.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// RelativeLuminance returns the relative luminance of the color c as defined
+// in WCAG 2.x, where 0 stands for the darkest black and 1 for the lightest
+// white.
+func relativeLuminance(c Color) float64 {
+	return 0.2126*linearize(c.R) + 0.7152*linearize(c.G) + 0.0722*linearize(c.B)
+}
+
+// IsDark reports whether white text offers better contrast on the color c than
+// black text does.
+func isDark(c Color) bool {
+	// NOTE: Contrast ratios against black and white are equal for the relative
+	// luminance of sqrt(1.05 * 0.05) - 0.05 ≈ 0.179.
+	return relativeLuminance(c) < 0.179
+}
+
+// LabDistance returns the Euclidean distance between two OKLab colors.
+func labDistance(l1, a1, b1, l2, a2, b2 float64) float64 {
+	return math.Sqrt((l1-l2)*(l1-l2) + (a1-a2)*(a1-a2) + (b1-b2)*(b1-b2))
+}
+
+// Linearize converts the sRGB channel value v to linear light in the range
+// [0, 1].
+func linearize(v uint8) float64 {
+	s := float64(v) / 255
+	if s <= 0.04045 {
+		return s / 12.92
+	}
+	return math.Pow((s+0.055)/1.055, 2.4)
+}
+
+// Delinearize converts linear light f to the sRGB channel value.
+func delinearize(f float64) uint8 {
+	f = clamp01(f)
+	if f <= 0.0031308 {
+		return channel(12.92 * f)
+	}
+	return channel(1.055*math.Pow(f, 1/2.4) - 0.055)
+}
+
+// Channel converts the channel value f in the range [0, 1] to uint8.
+func channel(f float64) uint8 {
+	return uint8(math.Round(clamp01(f) * 255))
+}
+
+// Clamp01 restricts f to the range [0, 1].
+func clamp01(f float64) float64 {
+	return math.Max(0, math.Min(1, f))
+}
@@ -0,0 +1,269 @@
+package termcols
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// near reports whether the colors c and d differ by at most one in each
+// channel to allow for rounding.
+func near(c, d Color) bool {
+	diff := func(a, b uint8) bool { return a-b <= 1 || b-a <= 1 }
+	return diff(c.R, d.R) && diff(c.G, d.G) && diff(c.B, d.B)
+}
+
+func TestParseHex(t *testing.T) {
+	cases := []struct {
+		s   string
+		exp Color
+		err error
+	}{
+		{"#ff8700", Color{255, 135, 0}, nil},
+		{"FF8700", Color{255, 135, 0}, nil},
+		{"#f80", Color{255, 136, 0}, nil},
+		{"#ABC", Color{170, 187, 204}, nil},
+		{"#000000", Color{0, 0, 0}, nil},
+
+		{"", Color{}, ErrColor},
+		{"#", Color{}, ErrColor},
+		{"#ff87", Color{}, ErrColor},
+		{"#ff870g", Color{}, ErrColor},
+		{"##fff", Color{}, ErrColor},
+		{"#ff8700ff", Color{}, ErrColor},
+	}
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			out, err := ParseHex(c.s)
+			if !errors.Is(err, c.err) || out != c.exp {
+				t.Errorf("Have: %v %v, want: %v %v", out, err, c.exp, c.err)
+			}
+		})
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	cases := []struct {
+		s   string
+		exp Color
+		err error
+	}{
+		{"#ff8700", Color{255, 135, 0}, nil},
+		{"208", Color{255, 135, 0}, nil},
+		{"white", Color{229, 229, 229}, nil},
+		{"Bright-Blue", Color{92, 92, 255}, nil},
+		{"grey23", Color{238, 238, 238}, nil},
+		{"bg=cyan", Color{0, 205, 205}, nil},
+		{"fg=#f80", Color{255, 136, 0}, nil},
+		{"redbbg", Color{255, 0, 0}, nil},
+		{"rgb8=bg:16", Color{0, 0, 0}, nil},
+		{"rgb24=fg:1:2:3", Color{1, 2, 3}, nil},
+		{"okabeito=fg:orange", Color{230, 159, 0}, nil},
+
+		{"", Color{}, ErrColor},
+		{"purple", Color{}, ErrColor},
+		{"#77", Color{}, ErrColor},
+		{"256", Color{}, ErrColor},
+		{"bold", Color{}, ErrColor},
+		{"default", Color{}, ErrColor},
+		{"bold red", Color{}, ErrColor},
+	}
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			out, err := ParseColor(c.s)
+			if !errors.Is(err, c.err) || out != c.exp {
+				t.Errorf("Have: %v %v, want: %v %v", out, err, c.exp, c.err)
+			}
+		})
+	}
+}
+
+func TestHex(t *testing.T) {
+	cases := []struct {
+		c   Color
+		exp string
+	}{
+		{Color{0, 0, 0}, "#000000"},
+		{Color{255, 135, 0}, "#ff8700"},
+		{Color{1, 2, 3}, "#010203"},
+	}
+	for _, c := range cases {
+		t.Run(c.exp, func(t *testing.T) {
+			if out := c.c.Hex(); out != c.exp {
+				t.Errorf("Have: %s, want: %s", out, c.exp)
+			}
+			if back, _ := ParseHex(c.exp); back != c.c {
+				t.Errorf("Have: %v, want: %v", back, c.c)
+			}
+		})
+	}
+}
+
+func TestHSL(t *testing.T) {
+	cases := []struct {
+		c       Color
+		h, s, l float64
+	}{
+		{Color{0, 0, 0}, 0, 0, 0},
+		{Color{255, 255, 255}, 0, 0, 1},
+		{Color{255, 0, 0}, 0, 1, 0.5},
+		{Color{0, 255, 0}, 120, 1, 0.5},
+		{Color{0, 0, 255}, 240, 1, 0.5},
+		{Color{255, 0, 255}, 300, 1, 0.5},
+		{Color{128, 128, 128}, 0, 0, 128.0 / 255},
+		{Color{255, 128, 0}, 30.1, 1, 0.5},
+	}
+	for _, c := range cases {
+		t.Run(c.c.Hex(), func(t *testing.T) {
+			h, s, l := c.c.HSL()
+			if math.Abs(h-c.h) > 0.1 || math.Abs(s-c.s) > 0.01 || math.Abs(l-c.l) > 0.01 {
+				t.Errorf("Have: %.2f %.2f %.2f, want: %.2f %.2f %.2f", h, s, l, c.h, c.s, c.l)
+			}
+			if out := HSL(h, s, l); out != c.c {
+				t.Errorf("Have: %v, want: %v", out, c.c)
+			}
+		})
+	}
+	if out := HSL(-240, 2, 0.5); out != (Color{0, 255, 0}) {
+		t.Errorf("Have: %v, want: %v", out, Color{0, 255, 0})
+	}
+}
+
+func TestOKLab(t *testing.T) {
+	cases := []struct {
+		c       Color
+		l, a, b float64
+	}{
+		{Color{0, 0, 0}, 0, 0, 0},
+		{Color{255, 255, 255}, 1, 0, 0},
+		{Color{255, 0, 0}, 0.628, 0.225, 0.126},
+		{Color{0, 0, 255}, 0.452, -0.032, -0.312},
+	}
+	for _, c := range cases {
+		t.Run(c.c.Hex(), func(t *testing.T) {
+			l, a, b := c.c.OKLab()
+			if math.Abs(l-c.l) > 0.001 || math.Abs(a-c.a) > 0.001 || math.Abs(b-c.b) > 0.001 {
+				t.Errorf("Have: %.3f %.3f %.3f, want: %.3f %.3f %.3f", l, a, b, c.l, c.a, c.b)
+			}
+		})
+	}
+	for _, c := range []Color{{12, 200, 99}, {255, 135, 0}, {1, 2, 3}, {250, 250, 240}} {
+		t.Run("round-trip-"+c.Hex(), func(t *testing.T) {
+			if out := OKLab(c.OKLab()); out != c {
+				t.Errorf("Have: %v, want: %v", out, c)
+			}
+		})
+	}
+}
+
+func TestPaletteColor(t *testing.T) {
+	cases := []struct {
+		i   uint8
+		exp Color
+	}{
+		{0, Color{0, 0, 0}},
+		{9, Color{255, 0, 0}},
+		{16, Color{0, 0, 0}},
+		{21, Color{0, 0, 255}},
+		{196, Color{255, 0, 0}},
+		{214, Color{255, 175, 0}},
+		{231, Color{255, 255, 255}},
+		{232, Color{8, 8, 8}},
+		{255, Color{238, 238, 238}},
+	}
+	for _, c := range cases {
+		t.Run(c.exp.Hex(), func(t *testing.T) {
+			if out := PaletteColor(c.i); out != c.exp {
+				t.Errorf("Have: %v, want: %v", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestPaletteIndex(t *testing.T) {
+	cases := []struct {
+		c   Color
+		exp uint8
+	}{
+		{Color{0, 0, 0}, 16},
+		{Color{255, 255, 255}, 231},
+		{Color{255, 0, 0}, 196},
+		{Color{255, 175, 0}, 214},
+		{Color{250, 170, 10}, 214},
+		{Color{128, 128, 128}, 244},
+		{Color{9, 9, 9}, 232},
+	}
+	for _, c := range cases {
+		t.Run(c.c.Hex(), func(t *testing.T) {
+			if out := c.c.PaletteIndex(); out != c.exp {
+				t.Errorf("Have: %d, want: %d", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestManipulation(t *testing.T) {
+	red := Color{255, 0, 0}
+	cases := []struct {
+		name string
+		out  Color
+		exp  Color
+	}{
+		{"lighten", red.Lighten(0.25), Color{255, 128, 128}},
+		{"lighten-max", red.Lighten(1), Color{255, 255, 255}},
+		{"darken", red.Darken(0.25), Color{128, 0, 0}},
+		{"darken-max", red.Darken(2), Color{0, 0, 0}},
+		{"saturate", Color{191, 64, 64}.Saturate(0.5), Color{255, 0, 0}},
+		{"desaturate", red.Saturate(-1), Color{128, 128, 128}},
+		{"complement", red.Complement(), Color{0, 255, 255}},
+		{"complement-gray", Color{99, 99, 99}.Complement(), Color{99, 99, 99}},
+		{"mix-start", red.Mix(Color{0, 0, 255}, 0), red},
+		{"mix-end", red.Mix(Color{0, 0, 255}, 1), Color{0, 0, 255}},
+		{"mix-clamp", red.Mix(Color{0, 0, 255}, 7), Color{0, 0, 255}},
+		{"mix-gray", Color{0, 0, 0}.Mix(Color{255, 255, 255}, 0.5), Color{99, 99, 99}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if !near(c.out, c.exp) {
+				t.Errorf("Have: %v, want: %v", c.out, c.exp)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	black, white := Color{0, 0, 0}, Color{255, 255, 255}
+	if d := black.Distance(black); d != 0 {
+		t.Errorf("Have: %f, want: 0", d)
+	}
+	if d := black.Distance(white); math.Abs(d-1) > 0.001 {
+		t.Errorf("Have: %f, want: 1", d)
+	}
+	red := Color{255, 0, 0}
+	if near, far := red.Distance(Color{250, 10, 10}), red.Distance(Color{0, 255, 0}); near >= far {
+		t.Errorf("Have: %f >= %f, want: %f < %f", near, far, near, far)
+	}
+	if d1, d2 := red.Distance(white), white.Distance(red); d1 != d2 {
+		t.Errorf("Have: %f != %f, want: equal distances", d1, d2)
+	}
+}
+
+func TestColorAttrs(t *testing.T) {
+	c := Color{255, 175, 0}
+	cases := []struct {
+		out SgrAttr
+		exp SgrAttr
+	}{
+		{c.Fg(), Rgb24(FG, 255, 175, 0)},
+		{c.Bg(), Rgb24(BG, 255, 175, 0)},
+		{c.Fg8(), Rgb8(FG, 214)},
+		{c.Bg8(), Rgb8(BG, 214)},
+	}
+	for _, c := range cases {
+		t.Run(string(c.exp), func(t *testing.T) {
+			if c.out != c.exp {
+				t.Errorf("Have: %q, want: %q", c.out, c.exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package termcols
+
+import (
+	"math"
+)
+
+// Minimum WCAG 2.x contrast ratios between text and its background. Large
+// text is at least 18 points, or 14 points when bold.
+const (
+	ContrastAA       = 4.5
+	ContrastAALarge  = 3.0
+	ContrastAAA      = 7.0
+	ContrastAAALarge = 4.5
+)
+
+// APCA constants of the 0.0.98G-4g version of the algorithm.
+const (
+	apcaNormBg     = 0.56
+	apcaNormTxt    = 0.57
+	apcaRevTxt     = 0.62
+	apcaRevBg      = 0.65
+	apcaBlkThrs    = 0.022
+	apcaBlkClmp    = 1.414
+	apcaScale      = 1.14
+	apcaLoOffset   = 0.027
+	apcaDeltaYMin  = 0.0005
+	apcaLoClip     = 0.1
+	apcaMainTrc    = 2.4
+	apcaRedCoeff   = 0.2126729
+	apcaGreenCoeff = 0.7151522
+	apcaBlueCoeff  = 0.0721750
+)
+
+// Contrast returns the WCAG 2.x contrast ratio between the colors a and b
+// computed from their relative luminance. It ranges from 1 for equal colors
+// to 21 for black and white, and the order of a and b does not matter.
+// Compare it against ContrastAA and the other minimums to check whether text
+// is readable.
+func Contrast(a, b Color) float64 {
+	la, lb := relativeLuminance(a), relativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// APCA returns the lightness contrast Lc of the text color on the bg
+// background color as defined by the Accessible Perceptual Contrast Algorithm
+// considered for WCAG 3. Unlike Contrast, it depends on which of the two
+// colors is the text. Dark text on light backgrounds yields positive values up
+// to about 106 and light text on dark backgrounds negative values down to
+// about -108. Absolute values of 60 and more are fit for body text.
+func APCA(text, bg Color) float64 {
+	yt, yb := apcaY(text), apcaY(bg)
+	if math.Abs(yb-yt) < apcaDeltaYMin {
+		return 0
+	}
+	if yb > yt {
+		sapc := (math.Pow(yb, apcaNormBg) - math.Pow(yt, apcaNormTxt)) * apcaScale
+		if sapc < apcaLoClip {
+			return 0
+		}
+		return (sapc - apcaLoOffset) * 100
+	}
+	sapc := (math.Pow(yb, apcaRevBg) - math.Pow(yt, apcaRevTxt)) * apcaScale
+	if sapc > -apcaLoClip {
+		return 0
+	}
+	return (sapc + apcaLoOffset) * 100
+}
+
+// ReadableOn returns the color of candidates that has the highest WCAG
+// contrast ratio with the bg background color, the first one on ties. It
+// picks either black or white when no candidates are given.
+func ReadableOn(bg Color, candidates ...Color) Color {
+	if len(candidates) == 0 {
+		candidates = []Color{{0, 0, 0}, {255, 255, 255}}
+	}
+	best, bestRatio := candidates[0], Contrast(bg, candidates[0])
+	for _, c := range candidates[1:] {
+		if r := Contrast(bg, c); r > bestRatio {
+			best, bestRatio = c, r
+		}
+	}
+	return best
+}
+
+// ApcaY returns the screen luminance of the color c estimated the way APCA
+// does it, with soft clamping of near-black colors.
+func apcaY(c Color) float64 {
+	ch := func(v uint8) float64 {
+		return math.Pow(float64(v)/255, apcaMainTrc)
+	}
+	y := apcaRedCoeff*ch(c.R) + apcaGreenCoeff*ch(c.G) + apcaBlueCoeff*ch(c.B)
+	if y < apcaBlkThrs {
+		y += math.Pow(apcaBlkThrs-y, apcaBlkClmp)
+	}
+	return y
+}
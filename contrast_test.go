@@ -0,0 +1,74 @@
+package termcols
+
+import (
+	"math"
+	"testing"
+)
+
+func TestContrast(t *testing.T) {
+	cases := []struct {
+		a, b Color
+		exp  float64
+	}{
+		{Color{0, 0, 0}, Color{255, 255, 255}, 21},
+		{Color{255, 255, 255}, Color{0, 0, 0}, 21},
+		{Color{119, 119, 119}, Color{255, 255, 255}, 4.48},
+		{Color{118, 118, 118}, Color{255, 255, 255}, 4.54},
+		{Color{255, 0, 0}, Color{255, 255, 255}, 4.00},
+		{Color{0, 0, 255}, Color{0, 0, 0}, 2.44},
+		{Color{12, 34, 56}, Color{12, 34, 56}, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.a.Hex()+"-"+c.b.Hex(), func(t *testing.T) {
+			if out := Contrast(c.a, c.b); math.Abs(out-c.exp) > 0.01 {
+				t.Errorf("Have: %.2f, want: %.2f", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestAPCA(t *testing.T) {
+	cases := []struct {
+		text, bg Color
+		exp      float64
+	}{
+		{Color{0, 0, 0}, Color{255, 255, 255}, 106.04},
+		{Color{255, 255, 255}, Color{0, 0, 0}, -107.88},
+		{Color{136, 136, 136}, Color{255, 255, 255}, 63.06},
+		{Color{255, 255, 255}, Color{136, 136, 136}, -68.54},
+		{Color{17, 34, 51}, Color{221, 238, 255}, 91.67},
+		{Color{100, 100, 100}, Color{100, 100, 100}, 0},
+		{Color{250, 250, 250}, Color{255, 255, 255}, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.text.Hex()+"-"+c.bg.Hex(), func(t *testing.T) {
+			if out := APCA(c.text, c.bg); math.Abs(out-c.exp) > 0.01 {
+				t.Errorf("Have: %.2f, want: %.2f", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestReadableOn(t *testing.T) {
+	black, white := Color{0, 0, 0}, Color{255, 255, 255}
+	cases := []struct {
+		name       string
+		bg         Color
+		candidates []Color
+		exp        Color
+	}{
+		{"default-light", Color{255, 215, 0}, nil, black},
+		{"default-dark", Color{0, 0, 128}, nil, white},
+		{"mid-gray", Color{119, 119, 119}, nil, black},
+		{"candidates", Color{40, 40, 40}, []Color{{90, 90, 90}, {255, 255, 0}, {200, 200, 200}}, Color{255, 255, 0}},
+		{"single", Color{255, 255, 255}, []Color{{250, 250, 250}}, Color{250, 250, 250}},
+		{"tie", Color{128, 128, 128}, []Color{{1, 2, 3}, {1, 2, 3}}, Color{1, 2, 3}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if out := ReadableOn(c.bg, c.candidates...); out != c.exp {
+				t.Errorf("Have: %v, want: %v", out, c.exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Package cursor implements control sequences that move the cursor, erase parts
+of the screen and control the terminal screen. Together with the SGR control
+sequences of the termcols package they suffice to build simple live-updating
+displays such as status lines and progress indicators.
+
+All control sequences are of type Seq, which is a string, so they can be
+written straight to the terminal or concatenated with regular text.
+
+The selection of control sequences follows the list of Control Sequence
+Introducer (CSI) commands available at [Wikipedia ANSI]. The private modes
+used to hide the cursor and to switch to the alternate screen buffer are not
+part of the ANSI standard, but they are supported by all common terminal
+emulators.
+
+# Usage
+
+	package main
+
+	import (
+		"fmt"
+		"time"
+
+		"github.com/mdm-code/termcols/cursor"
+	)
+
+	func main() {
+		fmt.Print(cursor.Hide)
+		defer fmt.Print(cursor.Show)
+		for i := 0; i <= 100; i += 10 {
+			fmt.Printf("%s%sProgress: %d%%", cursor.Column(1), cursor.EraseLine(cursor.EraseAll), i)
+			time.Sleep(100 * time.Millisecond)
+		}
+		fmt.Println()
+	}
+
+[Wikipedia ANSI]: https://en.wikipedia.org/wiki/ANSI_escape_code
+*/
+package cursor
+
+import (
+	"strconv"
+
+	"github.com/mdm-code/termcols"
+)
+
+// Cursor position and visibility
+const (
+	Save    Seq = termcols.Esc + "7"
+	Restore Seq = termcols.Esc + "8"
+	Hide    Seq = termcols.Csi + "?25l"
+	Show    Seq = termcols.Csi + "?25h"
+	Home    Seq = termcols.Csi + "H"
+)
+
+// Screen buffers
+const (
+	EnterAltScreen Seq = termcols.Csi + "?1049h"
+	LeaveAltScreen Seq = termcols.Csi + "?1049l"
+)
+
+// Scroll region
+const (
+	ResetScrollRegion Seq = termcols.Csi + "r"
+)
+
+// Erase modes
+const (
+	EraseToEnd   EraseMode = 0
+	EraseToStart EraseMode = 1
+	EraseAll     EraseMode = 2
+
+	// EraseScrollback applies only to EraseDisplay. It clears the entire
+	// screen along with all the lines saved in the scrollback buffer.
+	EraseScrollback EraseMode = 3
+)
+
+// Seq corresponds to a control sequence that moves the cursor, erases a part
+// of the screen or alters the way the terminal screen works. Unlike SGR
+// control sequences, Seq values have no lasting effect on how text is
+// displayed.
+type Seq string
+
+// EraseMode specifies which part of the screen or the line relative to the
+// cursor position gets erased by [EraseDisplay] and [EraseLine].
+type EraseMode uint8
+
+// Up returns the CUU (Cursor Up) control sequence that moves the cursor n
+// rows up. The cursor stops at the top edge of the screen.
+func Up(n uint) Seq {
+	return csi(n, 'A')
+}
+
+// Down returns the CUD (Cursor Down) control sequence that moves the cursor n
+// rows down. The cursor stops at the bottom edge of the screen.
+func Down(n uint) Seq {
+	return csi(n, 'B')
+}
+
+// Forward returns the CUF (Cursor Forward) control sequence that moves the
+// cursor n columns to the right. The cursor stops at the right edge of the
+// screen.
+func Forward(n uint) Seq {
+	return csi(n, 'C')
+}
+
+// Back returns the CUB (Cursor Back) control sequence that moves the cursor n
+// columns to the left. The cursor stops at the left edge of the screen.
+func Back(n uint) Seq {
+	return csi(n, 'D')
+}
+
+// NextLine returns the CNL (Cursor Next Line) control sequence that moves the
+// cursor to the beginning of the line n lines down.
+func NextLine(n uint) Seq {
+	return csi(n, 'E')
+}
+
+// PrevLine returns the CPL (Cursor Previous Line) control sequence that moves
+// the cursor to the beginning of the line n lines up.
+func PrevLine(n uint) Seq {
+	return csi(n, 'F')
+}
+
+// Column returns the CHA (Cursor Horizontal Absolute) control sequence that
+// moves the cursor to the column col of the current line. Columns are
+// numbered starting from 1.
+func Column(col uint) Seq {
+	return csi(col, 'G')
+}
+
+// Position returns the CUP (Cursor Position) control sequence that moves the
+// cursor to the row row and the column col. Both rows and columns are
+// numbered starting from 1, so Position(1, 1) moves the cursor to the top
+// left corner of the screen.
+func Position(row, col uint) Seq {
+	return Seq(termcols.Csi + strconv.FormatUint(uint64(row), 10) + ";" + strconv.FormatUint(uint64(col), 10) + "H")
+}
+
+// EraseDisplay returns the ED (Erase in Display) control sequence that clears
+// the part of the screen specified with the erase mode m. The cursor does not
+// move.
+func EraseDisplay(m EraseMode) Seq {
+	return csi(uint(m), 'J')
+}
+
+// EraseLine returns the EL (Erase in Line) control sequence that clears the
+// part of the current line specified with the erase mode m. The cursor does
+// not move. EraseScrollback is not a valid mode for EraseLine.
+func EraseLine(m EraseMode) Seq {
+	return csi(uint(m), 'K')
+}
+
+// ScrollUp returns the SU (Scroll Up) control sequence that scrolls the
+// content of the screen, or the scroll region when one is set, n lines up.
+// New lines are added at the bottom.
+func ScrollUp(n uint) Seq {
+	return csi(n, 'S')
+}
+
+// ScrollDown returns the SD (Scroll Down) control sequence that scrolls the
+// content of the screen, or the scroll region when one is set, n lines down.
+// New lines are added at the top.
+func ScrollDown(n uint) Seq {
+	return csi(n, 'T')
+}
+
+// ScrollRegion returns the DECSTBM (Set Top and Bottom Margins) control
+// sequence that limits scrolling to the rows in the range [top, bottom].
+// Rows are numbered starting from 1. Use [ResetScrollRegion] to make the
+// whole screen scroll again.
+func ScrollRegion(top, bottom uint) Seq {
+	return Seq(termcols.Csi + strconv.FormatUint(uint64(top), 10) + ";" + strconv.FormatUint(uint64(bottom), 10) + "r")
+}
+
+// Title returns the OSC 2 control sequence that sets the title of the
+// terminal window to the string s. Control characters are removed from s so
+// that they cannot terminate the sequence early.
+func Title(s string) Seq {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] == 0x7f {
+			continue
+		}
+		b = append(b, s[i])
+	}
+	return Seq(termcols.Osc + "2;" + string(b) + termcols.St)
+}
+
+// Csi returns a control sequence with a single numeric parameter n and the
+// final byte f.
+func csi(n uint, f byte) Seq {
+	return Seq(termcols.Csi + strconv.FormatUint(uint64(n), 10) + string(f))
+}
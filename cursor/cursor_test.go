@@ -0,0 +1,47 @@
+package cursor
+
+import (
+	"testing"
+)
+
+func TestSeq(t *testing.T) {
+	cases := []struct {
+		name   string
+		seq    Seq
+		expOut Seq
+	}{
+		{"up", Up(3), Seq("\033[3A")},
+		{"down", Down(1), Seq("\033[1B")},
+		{"forward", Forward(12), Seq("\033[12C")},
+		{"back", Back(7), Seq("\033[7D")},
+		{"next-line", NextLine(2), Seq("\033[2E")},
+		{"prev-line", PrevLine(4), Seq("\033[4F")},
+		{"column", Column(1), Seq("\033[1G")},
+		{"position", Position(10, 42), Seq("\033[10;42H")},
+		{"erase-display-end", EraseDisplay(EraseToEnd), Seq("\033[0J")},
+		{"erase-display-all", EraseDisplay(EraseAll), Seq("\033[2J")},
+		{"erase-display-scrollback", EraseDisplay(EraseScrollback), Seq("\033[3J")},
+		{"erase-line-start", EraseLine(EraseToStart), Seq("\033[1K")},
+		{"erase-line-all", EraseLine(EraseAll), Seq("\033[2K")},
+		{"scroll-up", ScrollUp(5), Seq("\033[5S")},
+		{"scroll-down", ScrollDown(6), Seq("\033[6T")},
+		{"scroll-region", ScrollRegion(2, 20), Seq("\033[2;20r")},
+		{"reset-scroll-region", ResetScrollRegion, Seq("\033[r")},
+		{"save", Save, Seq("\0337")},
+		{"restore", Restore, Seq("\0338")},
+		{"hide", Hide, Seq("\033[?25l")},
+		{"show", Show, Seq("\033[?25h")},
+		{"home", Home, Seq("\033[H")},
+		{"enter-alt-screen", EnterAltScreen, Seq("\033[?1049h")},
+		{"leave-alt-screen", LeaveAltScreen, Seq("\033[?1049l")},
+		{"title", Title("tcols"), Seq("\033]2;tcols\033\\")},
+		{"title-control-chars", Title("a\033\\b\a"), Seq("\033]2;a\\b\033\\")},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.seq != c.expOut {
+				t.Errorf("Have: %q, want: %q", c.seq, c.expOut)
+			}
+		})
+	}
+}
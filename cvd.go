@@ -0,0 +1,158 @@
+package termcols
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Color vision deficiencies that can be simulated with Color.Simulate.
+const (
+	Protanopia Deficiency = iota + 1
+	Deuteranopia
+	Tritanopia
+	Achromatopsia
+)
+
+// Deficiency is a kind of color vision deficiency.
+type Deficiency int
+
+// OkabeIto maps lowercase names of the Okabe-Ito palette colors onto them.
+var okabeIto map[string]Color = map[string]Color{
+	"black":         {0, 0, 0},
+	"orange":        {230, 159, 0},
+	"skyblue":       {86, 180, 233},
+	"bluishgreen":   {0, 158, 115},
+	"yellow":        {240, 228, 66},
+	"blue":          {0, 114, 178},
+	"vermillion":    {213, 94, 0},
+	"reddishpurple": {204, 121, 167},
+}
+
+// OkabeIto returns the color of the Okabe-Ito palette designed to remain
+// distinguishable for people with any form of color vision deficiency. The
+// name is one of black, orange, skyblue, bluishgreen, yellow, blue,
+// vermillion and reddishpurple in any case, the same names that MapColor
+// takes in the okabeito=[fg|bg]:[name] pattern. It reports false for any other
+// name.
+func OkabeIto(name string) (Color, bool) {
+	c, ok := okabeIto[strings.ToLower(name)]
+	return c, ok
+}
+
+// CvdMatrices hold linear RGB transforms of Machado et al. (2009) simulating
+// protanopia, deuteranopia and tritanopia at full severity.
+var cvdMatrices = map[Deficiency][3][3]float64{
+	Protanopia: {
+		{0.152286, 1.052583, -0.204868},
+		{0.114503, 0.786281, 0.099216},
+		{-0.003882, -0.048116, 1.051998},
+	},
+	Deuteranopia: {
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	},
+	Tritanopia: {
+		{1.255528, -0.076749, -0.178779},
+		{-0.078411, 0.930809, 0.147602},
+		{0.004733, 0.691367, 0.303900},
+	},
+}
+
+func (d Deficiency) String() string {
+	switch d {
+	case Protanopia:
+		return "protanopia"
+	case Deuteranopia:
+		return "deuteranopia"
+	case Tritanopia:
+		return "tritanopia"
+	case Achromatopsia:
+		return "achromatopsia"
+	}
+	return "Deficiency(" + strconv.Itoa(int(d)) + ")"
+}
+
+// Simulate returns the color c the way it is seen by people with the color
+// vision deficiency d. Protanopia, deuteranopia and tritanopia are simulated
+// with the model of Machado et al. (2009), and achromatopsia turns colors into
+// shades of gray of the same relative luminance. Unknown deficiencies leave c
+// unchanged.
+func (c Color) Simulate(d Deficiency) Color {
+	if d == Achromatopsia {
+		v := delinearize(relativeLuminance(c))
+		return Color{v, v, v}
+	}
+	m, ok := cvdMatrices[d]
+	if !ok {
+		return c
+	}
+	in := [3]float64{linearize(c.R), linearize(c.G), linearize(c.B)}
+	var out [3]uint8
+	for i, row := range m {
+		out[i] = delinearize(row[0]*in[0] + row[1]*in[1] + row[2]*in[2])
+	}
+	return Color{out[0], out[1], out[2]}
+}
+
+// SimulateAttr rewrites colors of the SGR control sequence a the way they are
+// seen by people with the color vision deficiency d. Named, 8-bit and 24-bit
+// colors are all turned into 24-bit colors, the 16 ANSI colors approximated
+// with their xterm defaults. Other parameters are kept, and a is returned as
+// it is when it is not a well-formed SGR control sequence or holds no colors.
+func SimulateAttr(a SgrAttr, d Deficiency) SgrAttr {
+	params, ok := a.Params()
+	if !ok {
+		return a
+	}
+	out := make([]string, 0, len(params))
+	var changed bool
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		l, c, n, ok := paramColor(params[i:])
+		if !ok {
+			out = append(out, strconv.Itoa(p))
+			continue
+		}
+		s := c.Simulate(d)
+		out = append(out, string(l[len(Csi):]), "2",
+			strconv.Itoa(int(s.R)), strconv.Itoa(int(s.G)), strconv.Itoa(int(s.B)))
+		i += n - 1
+		changed = true
+	}
+	if !changed {
+		return a
+	}
+	return SgrAttr(Csi + strings.Join(out, ";") + "m")
+}
+
+// ParamColor interprets the color at the start of the SGR parameters params.
+// It returns the layer and the color along with the number of parameters it
+// takes up, or false when params do not start with a color.
+func paramColor(params []int) (Layer, Color, int, bool) {
+	p := params[0]
+	switch {
+	case p >= 30 && p <= 37:
+		return FG, PaletteColor(uint8(p - 30)), 1, true
+	case p >= 90 && p <= 97:
+		return FG, PaletteColor(uint8(p - 90 + 8)), 1, true
+	case p >= 40 && p <= 47:
+		return BG, PaletteColor(uint8(p - 40)), 1, true
+	case p >= 100 && p <= 107:
+		return BG, PaletteColor(uint8(p - 100 + 8)), 1, true
+	case p == 38, p == 48:
+		l := FG
+		if p == 48 {
+			l = BG
+		}
+		rest := params[1:]
+		switch {
+		case len(rest) >= 2 && rest[0] == 5 && validUint8(rest[1]):
+			return l, PaletteColor(uint8(rest[1])), 3, true
+		case len(rest) >= 4 && rest[0] == 2 &&
+			validUint8(rest[1]) && validUint8(rest[2]) && validUint8(rest[3]):
+			return l, Color{uint8(rest[1]), uint8(rest[2]), uint8(rest[3])}, 5, true
+		}
+	}
+	return "", Color{}, 0, false
+}
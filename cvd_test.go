@@ -0,0 +1,134 @@
+package termcols
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	cases := []struct {
+		name string
+		c    Color
+		d    Deficiency
+		exp  Color
+	}{
+		{"white-protanopia", Color{255, 255, 255}, Protanopia, Color{255, 255, 255}},
+		{"black-tritanopia", Color{0, 0, 0}, Tritanopia, Color{0, 0, 0}},
+		{"red-protanopia", Color{255, 0, 0}, Protanopia, Color{109, 95, 0}},
+		{"red-deuteranopia", Color{255, 0, 0}, Deuteranopia, Color{163, 144, 0}},
+		{"blue-tritanopia", Color{0, 0, 255}, Tritanopia, Color{0, 108, 149}},
+		{"red-achromatopsia", Color{255, 0, 0}, Achromatopsia, Color{127, 127, 127}},
+		{"gray-achromatopsia", Color{99, 99, 99}, Achromatopsia, Color{99, 99, 99}},
+		{"unknown", Color{1, 2, 3}, Deficiency(0), Color{1, 2, 3}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if out := c.c.Simulate(c.d); !near(out, c.exp) {
+				t.Errorf("Have: %v, want: %v", out, c.exp)
+			}
+		})
+	}
+}
+
+// TestSimulateConfusion checks that the difference between red and green
+// along the red-green axis of OKLab vanishes for people with red-green
+// deficiencies.
+func TestSimulateConfusion(t *testing.T) {
+	red, green := Color{213, 0, 0}, Color{0, 160, 0}
+	_, ar, _ := red.OKLab()
+	_, ag, _ := green.OKLab()
+	for _, d := range []Deficiency{Protanopia, Deuteranopia} {
+		t.Run(d.String(), func(t *testing.T) {
+			_, sr, _ := red.Simulate(d).OKLab()
+			_, sg, _ := green.Simulate(d).OKLab()
+			if diff := math.Abs(sr - sg); diff > math.Abs(ar-ag)/10 {
+				t.Errorf("Have: %.3f, want: at most %.3f", diff, math.Abs(ar-ag)/10)
+			}
+		})
+	}
+}
+
+// TestOkabeIto checks that colors of the Okabe-Ito palette remain apart for
+// people with any of the dichromacies.
+func TestOkabeIto(t *testing.T) {
+	for _, d := range []Deficiency{Protanopia, Deuteranopia, Tritanopia} {
+		t.Run(d.String(), func(t *testing.T) {
+			for n1, c1 := range okabeIto {
+				for n2, c2 := range okabeIto {
+					if n1 >= n2 {
+						continue
+					}
+					if dist := c1.Simulate(d).Distance(c2.Simulate(d)); dist < 0.07 {
+						t.Errorf("Have: %s and %s %.3f apart, want: at least 0.07", n1, n2, dist)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestOkabeItoLookup(t *testing.T) {
+	cases := []struct {
+		name string
+		exp  Color
+		ok   bool
+	}{
+		{"orange", Color{230, 159, 0}, true},
+		{"ReddishPurple", Color{204, 121, 167}, true},
+		{"purple", Color{}, false},
+		{"", Color{}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out, ok := OkabeIto(c.name)
+			if out != c.exp || ok != c.ok {
+				t.Errorf("Have: %v %v, want: %v %v", out, ok, c.exp, c.ok)
+			}
+		})
+	}
+}
+
+func TestDeficiencyString(t *testing.T) {
+	cases := []struct {
+		d   Deficiency
+		exp string
+	}{
+		{Protanopia, "protanopia"},
+		{Deuteranopia, "deuteranopia"},
+		{Tritanopia, "tritanopia"},
+		{Achromatopsia, "achromatopsia"},
+		{Deficiency(9), "Deficiency(9)"},
+	}
+	for _, c := range cases {
+		t.Run(c.exp, func(t *testing.T) {
+			if out := c.d.String(); out != c.exp {
+				t.Errorf("Have: %s, want: %s", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestSimulateAttr(t *testing.T) {
+	cases := []struct {
+		a   SgrAttr
+		exp SgrAttr
+	}{
+		{RedFg, Csi + "38;2;101;101;101m"},
+		{RedBbg, Csi + "48;2;127;127;127m"},
+		{Rgb8(FG, 196), Csi + "38;2;127;127;127m"},
+		{Rgb24(BG, 255, 0, 0), Csi + "48;2;127;127;127m"},
+		{Csi + "1;31;4m", Csi + "1;38;2;101;101;101;4m"},
+		{Bold, Bold},
+		{Reset, Reset},
+		{Csi + "38;5m", Csi + "38;5m"},
+		{Csi + "2J", Csi + "2J"},
+		{"text", "text"},
+	}
+	for _, c := range cases {
+		t.Run(string(c.a), func(t *testing.T) {
+			if out := SimulateAttr(c.a, Achromatopsia); out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+		})
+	}
+}
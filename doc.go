@@ -17,9 +17,46 @@ The same applies to 8-bit and 24-bit colors: there is no guarantee that these
 escape sequences are supported will be rendered properly on some terminals.
 Results may vary, so it is good practice to test it first for compatibility.
 
+The Color type holds 24-bit colors that can be converted to and from hex
+strings, HSL, OKLab and the 256-color palette, lightened, darkened, mixed and
+compared before they are turned into an SgrAttr with its Fg and Bg methods.
+
+Contrast and APCA tell whether text in one color is readable on another one,
+and ReadableOn picks the most readable foreground for a given background.
+
+Color.Simulate and SimulateAttr show colors the way they are seen by people
+with a color vision deficiency, and OkabeIto returns the colors of a palette
+that remains distinguishable for them.
+
+A Renderer is bound to a writer and downgrades attributes to the color
+profile detected for it with DetectProfile, so 24-bit colors turn into 8-bit
+or ANSI colors where needed and all attributes are stripped when the writer is
+not a terminal. The NO_COLOR and CLICOLOR_FORCE environment variables are
+honored.
+
+Width measures the number of terminal cells taken up by text with escape
+sequences in it, and Truncate, PadRight, PadLeft, Center and Wrap lay out
+colorized text by its visible width without splitting escape sequences.
+
+RenderImage draws an image.Image with half block characters in 24-bit colors
+downgraded to a given Profile, which is enough for logos on splash screens.
+The sixel subpackage encodes images as Sixel graphics for terminals that
+display real pixels.
+
+The termcolstest subpackage provides readable forms of control sequences,
+style-aware comparisons and golden files for testing colored output.
+
+Terminals can be asked about their default foreground and background colors
+with QueryColors, and about the colors of their palette with QueryPalette.
+IsDarkBackground builds on top of these to tell whether the terminal uses a
+dark or a light theme, which helps pick colors that remain readable.
+
 The package has two public functions MapColor and MapColors that accept string
 values to try and map it onto a valid SgrAttr, however, it has been made
 implemented to simplify the terminal tcols command.
+Besides the names of the predefined attributes, they understand a natural
+style grammar such as "bold bright-red on blue" described with MapColors.
+Name goes the other way and returns the name of a single attribute.
 
 # Usage
 
@@ -0,0 +1,159 @@
+package termcols
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Reasons of style mapping failures
+const (
+	ReasonUnknownName Reason = iota + 1
+	ReasonOutOfRange
+	ReasonBadLayer
+	ReasonMalformed
+)
+
+// MaxSuggestions caps the number of suggestions offered with a StyleError.
+const maxSuggestions = 5
+
+// Reason tells why a style could not be mapped onto an SgrAttr.
+type Reason int
+
+// StyleError describes the style string that MapColor or MapColors failed to
+// map onto an SgrAttr. It matches ErrMap with [errors.Is], so callers not
+// interested in the details can keep checking for ErrMap.
+type StyleError struct {
+	// Token is the offending style string.
+	Token string
+
+	// Index is the position of Token in the slice passed to MapColors. It is
+	// always 0 for MapColor.
+	Index int
+
+	// Reason tells what is wrong with Token.
+	Reason Reason
+
+	// Suggestions lists valid style strings similar to Token, the most
+	// similar one first. It is empty when there is nothing close enough.
+	Suggestions []string
+}
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonUnknownName:
+		return "unknown color or style name"
+	case ReasonOutOfRange:
+		return "color component out of range [0, 255]"
+	case ReasonBadLayer:
+		return "layer must be either fg or bg"
+	case ReasonMalformed:
+		return "malformed color pattern"
+	}
+	return "Reason(" + strconv.Itoa(int(r)) + ")"
+}
+
+func (e *StyleError) Error() string {
+	msg := fmt.Sprintf("style %q (argument %d): %s", e.Token, e.Index+1, e.Reason)
+	if len(e.Suggestions) == 0 {
+		return msg
+	}
+	quoted := make([]string, len(e.Suggestions))
+	for i, s := range e.Suggestions {
+		quoted[i] = strconv.Quote(s)
+	}
+	return msg + "; did you mean " + strings.Join(quoted, ", ") + "?"
+}
+
+// Unwrap makes StyleError match ErrMap.
+func (e *StyleError) Unwrap() error {
+	return ErrMap
+}
+
+// SuggestNames returns names of predefined colors and styles similar to the
+// string s. Only the names kept in nameMap are suggested, so that the
+// misspelled yellobfg is not.
+func suggestNames(s string) []string {
+	names := make([]string, 0, len(nameMap))
+	for _, name := range nameMap {
+		names = append(names, name)
+	}
+	return suggestFrom(s, names)
+}
+
+// SuggestFrom returns those of names that are within a small edit distance of
+// the string s or start with s, sorted by the distance.
+func suggestFrom(s string, names []string) []string {
+	s = strings.ToLower(s)
+	max := 1
+	switch {
+	case len(s) > 8:
+		max = 3
+	case len(s) > 4:
+		max = 2
+	}
+	type candidate struct {
+		name string
+		dist int
+	}
+	var cs []candidate
+	for _, name := range names {
+		d := editDistance(s, name)
+		if d <= max || (len(s) >= 3 && strings.HasPrefix(name, s)) {
+			cs = append(cs, candidate{name, d})
+		}
+	}
+	sort.Slice(cs, func(i, j int) bool {
+		if cs[i].dist != cs[j].dist {
+			return cs[i].dist < cs[j].dist
+		}
+		return cs[i].name < cs[j].name
+	})
+	if len(cs) > maxSuggestions {
+		cs = cs[:maxSuggestions]
+	}
+	var result []string
+	for _, c := range cs {
+		result = append(result, c.name)
+	}
+	return result
+}
+
+// SuggestLayers returns the rgb pattern made up of prefix, layer and rest
+// with the invalid layer replaced with fg and bg, the variant closer to the
+// invalid layer first.
+func suggestLayers(prefix, layer, rest string) []string {
+	result := []string{prefix + "fg" + rest, prefix + "bg" + rest}
+	if editDistance(strings.ToLower(layer), "bg") < editDistance(strings.ToLower(layer), "fg") {
+		result[0], result[1] = result[1], result[0]
+	}
+	return result
+}
+
+// EditDistance computes the optimal string alignment distance between a and
+// b, that is the Levenshtein distance that also counts transpositions of two
+// adjacent characters as a single edit.
+func editDistance(a, b string) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
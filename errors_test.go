@@ -0,0 +1,298 @@
+package termcols
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStyleError(t *testing.T) {
+	cases := []struct {
+		name        string
+		styles      []string
+		token       string
+		index       int
+		reason      Reason
+		suggestions []string
+	}{
+		{
+			"typo",
+			[]string{"bold", "italics"},
+			"italics",
+			1,
+			ReasonUnknownName,
+			[]string{"italic"},
+		},
+		{
+			"transposition",
+			[]string{"redgb"},
+			"redgb",
+			0,
+			ReasonUnknownName,
+			[]string{"redbg", "redbbg", "redfg"},
+		},
+		{
+			"prefix",
+			[]string{"strike", "underline", "blu"},
+			"blu",
+			2,
+			ReasonUnknownName,
+			[]string{"bluebg", "bluefg", "bluebbg", "bluebfg"},
+		},
+		{
+			"natural-fallback",
+			[]string{"bleu"},
+			"bleu",
+			0,
+			ReasonUnknownName,
+			[]string{"blue"},
+		},
+		{
+			"natural-on",
+			[]string{"bold", "on", "bright-rde"},
+			"bright-rde",
+			2,
+			ReasonUnknownName,
+			[]string{"bright-red", "bright-blue", "bright-green"},
+		},
+		{
+			"natural-on-missing",
+			[]string{"bold on"},
+			"on",
+			0,
+			ReasonMalformed,
+			nil,
+		},
+		{
+			"natural-layer",
+			[]string{"italic", "underline fg=gren"},
+			"fg=gren",
+			1,
+			ReasonUnknownName,
+			[]string{"fg=green"},
+		},
+		{
+			"natural-index",
+			[]string{"on 256"},
+			"256",
+			0,
+			ReasonOutOfRange,
+			nil,
+		},
+		{
+			"natural-grey",
+			[]string{"grey24"},
+			"grey24",
+			0,
+			ReasonMalformed,
+			nil,
+		},
+		{
+			"natural-hex",
+			[]string{"bg=#ff00"},
+			"bg=#ff00",
+			0,
+			ReasonMalformed,
+			nil,
+		},
+		{
+			"okabeito-name",
+			[]string{"okabeito=fg:vermilion"},
+			"okabeito=fg:vermilion",
+			0,
+			ReasonUnknownName,
+			[]string{"okabeito=fg:vermillion"},
+		},
+		{
+			"okabeito-layer",
+			[]string{"okabeito=gf:blue"},
+			"okabeito=gf:blue",
+			0,
+			ReasonBadLayer,
+			[]string{"okabeito=fg:blue", "okabeito=bg:blue"},
+		},
+		{
+			"okabeito-malformed",
+			[]string{"okabeito=blue"},
+			"okabeito=blue",
+			0,
+			ReasonMalformed,
+			nil,
+		},
+		{
+			"blank",
+			[]string{"bold", " "},
+			" ",
+			1,
+			ReasonUnknownName,
+			nil,
+		},
+		{
+			"nothing-close",
+			[]string{"purplefg"},
+			"purplefg",
+			0,
+			ReasonUnknownName,
+			nil,
+		},
+		{
+			"bad-layer",
+			[]string{"bold", "rgb8=gb:12"},
+			"rgb8=gb:12",
+			1,
+			ReasonBadLayer,
+			[]string{"rgb8=bg:12", "rgb8=fg:12"},
+		},
+		{
+			"bad-layer-24",
+			[]string{"RGB24=layer:1:2:3"},
+			"RGB24=layer:1:2:3",
+			0,
+			ReasonBadLayer,
+			[]string{"RGB24=fg:1:2:3", "RGB24=bg:1:2:3"},
+		},
+		{
+			"out-of-range",
+			[]string{"rgb24=fg:1:2:300"},
+			"rgb24=fg:1:2:300",
+			0,
+			ReasonOutOfRange,
+			nil,
+		},
+		{
+			"too-few-components",
+			[]string{"rgb24=fg:1:2"},
+			"rgb24=fg:1:2",
+			0,
+			ReasonMalformed,
+			nil,
+		},
+		{
+			"not-a-number",
+			[]string{"rgb8=fg:red"},
+			"rgb8=fg:red",
+			0,
+			ReasonMalformed,
+			nil,
+		},
+		{
+			"adaptive-missing-dark",
+			[]string{"adaptive=bluefg"},
+			"adaptive=bluefg",
+			0,
+			ReasonMalformed,
+			nil,
+		},
+		{
+			"adaptive-typo",
+			[]string{"adaptive=blakfg/whitefg"},
+			"adaptive=blakfg/whitefg",
+			0,
+			ReasonUnknownName,
+			[]string{
+				"adaptive=blackfg/whitefg",
+				"adaptive=blackbfg/whitefg",
+				"adaptive=blackbg/whitefg",
+				"adaptive=bluefg/whitefg",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := MapColors(c.styles)
+			if !errors.Is(err, ErrMap) {
+				t.Fatalf("Have: %v, want: %v", err, ErrMap)
+			}
+			var se *StyleError
+			if !errors.As(err, &se) {
+				t.Fatalf("Have: %T, want: %T", err, se)
+			}
+			if se.Token != c.token {
+				t.Errorf("Have: %q, want: %q", se.Token, c.token)
+			}
+			if se.Index != c.index {
+				t.Errorf("Have: %d, want: %d", se.Index, c.index)
+			}
+			if se.Reason != c.reason {
+				t.Errorf("Have: %s, want: %s", se.Reason, c.reason)
+			}
+			if !reflect.DeepEqual(se.Suggestions, c.suggestions) {
+				t.Errorf("Have: %q, want: %q", se.Suggestions, c.suggestions)
+			}
+		})
+	}
+}
+
+func TestStyleErrorMessage(t *testing.T) {
+	cases := []struct {
+		err *StyleError
+		exp string
+	}{
+		{
+			&StyleError{"italics", 1, ReasonUnknownName, []string{"italic"}},
+			`style "italics" (argument 2): unknown color or style name; did you mean "italic"?`,
+		},
+		{
+			&StyleError{"rgb8=gb:12", 0, ReasonBadLayer, []string{"rgb8=bg:12", "rgb8=fg:12"}},
+			`style "rgb8=gb:12" (argument 1): layer must be either fg or bg; did you mean "rgb8=bg:12", "rgb8=fg:12"?`,
+		},
+		{
+			&StyleError{"rgb8=fg:256", 0, ReasonOutOfRange, nil},
+			`style "rgb8=fg:256" (argument 1): color component out of range [0, 255]`,
+		},
+		{
+			&StyleError{"x", 0, Reason(42), nil},
+			`style "x" (argument 1): Reason(42)`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.exp, func(t *testing.T) {
+			if out := c.err.Error(); out != c.exp {
+				t.Errorf("Have: %s, want: %s", out, c.exp)
+			}
+		})
+	}
+}
+
+// Test that the misspelled legacy name is never suggested.
+func TestSuggestNamesLegacy(t *testing.T) {
+	for _, s := range []string{"yelobfg", "yellobf", "yellowbf", "yelowbfg"} {
+		t.Run(s, func(t *testing.T) {
+			out := suggestNames(s)
+			var found bool
+			for _, name := range out {
+				if name == "yellobfg" {
+					t.Errorf("Have: %q, want no %q", out, "yellobfg")
+				}
+				found = found || name == "yellowbfg"
+			}
+			if !found {
+				t.Errorf("Have: %q, want %q", out, "yellowbfg")
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		exp  int
+	}{
+		{"", "", 0},
+		{"", "bold", 4},
+		{"bold", "bold", 0},
+		{"bolt", "bold", 1},
+		{"italics", "italic", 1},
+		{"redgb", "redbg", 1},
+		{"redgb", "redfg", 2},
+		{"ca", "abc", 3},
+		{"kitten", "sitting", 3},
+	}
+	for _, c := range cases {
+		t.Run(c.a+"-"+c.b, func(t *testing.T) {
+			if out := editDistance(c.a, c.b); out != c.exp {
+				t.Errorf("Have: %d, want: %d", out, c.exp)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package termcols_test
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/mdm-code/termcols"
 )
@@ -20,6 +21,54 @@ func ExampleColorize() {
 	// Output: [31m[4m[48;2;120;255;54mColorized text![0m
 }
 
+// ExampleAppendColorize shows how to colorize text into a reusable buffer
+// instead of allocating a new string on every call.
+func ExampleAppendColorize() {
+	buf := make([]byte, 0, 64)
+	for _, lvl := range []string{"INFO", "WARN"} {
+		buf = termcols.AppendColorize(buf[:0], lvl, termcols.Bold)
+		buf = append(buf, " ready"...)
+		fmt.Println(string(buf))
+	}
+	// Output:
+	// [1mINFO[0m ready
+	// [1mWARN[0m ready
+}
+
+// ExampleWriteColorized shows how to write colorized text straight to an
+// io.Writer.
+func ExampleWriteColorized() {
+	termcols.WriteColorized(os.Stdout, "Colorized text!", termcols.GreenFg)
+	fmt.Println()
+	// Output: [32mColorized text![0m
+}
+
+// ExampleCombine shows how to merge several attributes into a single control
+// sequence.
+func ExampleCombine() {
+	attr := termcols.Combine(
+		termcols.Bold,
+		termcols.BlueFg,
+		termcols.Rgb24(termcols.BG, 1, 2, 3),
+	)
+	fmt.Printf("%q\n", attr)
+	// Output: "\x1b[1;34;48;2;1;2;3m"
+}
+
+// ExampleColor shows how to derive related colors from a single base color.
+func ExampleColor() {
+	orange, _ := termcols.ParseHex("#ff8700")
+	fmt.Println(orange.Darken(0.2).Hex())
+	fmt.Println(orange.Complement().Hex())
+	fmt.Println(orange.Mix(termcols.Color{R: 255, G: 255, B: 255}, 0.5).Hex())
+	fmt.Println(orange.PaletteIndex())
+	// Output:
+	// #995100
+	// #0078ff
+	// #ffc69b
+	// 208
+}
+
 func ExampleRgb8() {
 	attr := termcols.Rgb8(termcols.FG, 12)
 	fmt.Printf("%sColorized text!%s", attr, termcols.Reset)
@@ -43,3 +92,40 @@ func ExampleMapColors() {
 	fmt.Printf("%s%s%sColorized text!%s", attrs[0], attrs[1], attrs[2], termcols.Reset)
 	// Output: [34m[43m[9mColorized text![0m
 }
+
+func ExampleTransition() {
+	from := termcols.NewState(termcols.Bold, termcols.Italic, termcols.RedFg)
+	to := termcols.NewState(termcols.Italic, termcols.BlueFg)
+	fmt.Printf("%q", termcols.Transition(from, to))
+	// Output: "\x1b[22;34m"
+}
+
+func ExampleAdaptive() {
+	termcols.SetDarkBackground(true)
+	link := termcols.Adaptive{Light: termcols.BlueFg, Dark: termcols.CyanBfg}
+	s := termcols.Colorize("Colorized text!", termcols.Underline, link.SgrAttr())
+	fmt.Println(s)
+	// Output: [4m[96mColorized text![0m
+}
+
+// ExampleRenderer shows how a Renderer downgrades colors to the profile of
+// its writer, here set explicitly to the 256-color palette.
+func ExampleRenderer() {
+	r := termcols.NewRenderer(os.Stdout)
+	r.SetProfile(termcols.ANSI256)
+	fmt.Println(r.Colorize("Colorized text!", termcols.Rgb24(termcols.FG, 255, 135, 0)))
+	// Output: [38;5;208mColorized text![0m
+}
+
+// ExampleWrap shows how styled text is wrapped so that each line carries its
+// own style.
+func ExampleWrap() {
+	s := termcols.Colorize("The quick brown fox", termcols.Bold)
+	fmt.Printf("%q\n", termcols.Wrap(s, 10))
+	fmt.Printf("%q\n", termcols.Truncate(s, 8, "…"))
+	fmt.Printf("%q\n", termcols.PadRight(s, 21))
+	// Output:
+	// "\x1b[1mThe quick\x1b[0m\n\x1b[1mbrown fox\x1b[0m"
+	// "\x1b[1mThe qui…\x1b[0m"
+	// "\x1b[1mThe quick brown fox\x1b[0m  "
+}
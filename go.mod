@@ -4,4 +4,4 @@ go 1.21
 
 require golang.org/x/term v0.12.0
 
-require golang.org/x/sys v0.12.0 // indirect
+require golang.org/x/sys v0.12.0
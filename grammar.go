@@ -0,0 +1,153 @@
+package termcols
+
+import (
+	"strings"
+)
+
+// NaturalColors lists named colors of the natural style grammar. Each entry
+// holds the foreground, bright foreground, background and bright background
+// variants of the color in that order.
+var naturalColors map[string][4]SgrAttr = map[string][4]SgrAttr{
+	"black":   {BlackFg, BlackBfg, BlackBg, BlackBbg},
+	"red":     {RedFg, RedBfg, RedBg, RedBbg},
+	"green":   {GreenFg, GreenBfg, GreenBg, GreenBbg},
+	"yellow":  {YellowFg, YellowBfg, YellowBg, YellowBbg},
+	"blue":    {BlueFg, BlueBfg, BlueBg, BlueBbg},
+	"magenta": {MagentaFg, MagentaBfg, MagentaBg, MagentaBbg},
+	"cyan":    {CyanFg, CyanBfg, CyanBg, CyanBbg},
+	"white":   {WhiteFg, WhiteBfg, WhiteBg, WhiteBbg},
+}
+
+const (
+	// BrightPrefix marks the bright variant of a named color.
+	brightPrefix = "bright-"
+
+	// OnKeyword makes the color that follows it apply to the background.
+	onKeyword = "on"
+)
+
+// NaturalTerm maps the single term s of the natural style grammar onto an
+// SgrAttr. A bare color applies to the foreground, while fg= and bg= prefixes
+// pick the layer explicitly.
+func naturalTerm(s string) (SgrAttr, bool) {
+	l := FG
+	if len(s) > 3 && s[2] == '=' {
+		switch strings.ToLower(s[:2]) {
+		case "fg":
+		case "bg":
+			l = BG
+		default:
+			return "", false
+		}
+		s = s[3:]
+	}
+	return naturalColor(s, l)
+}
+
+// NaturalColor maps the color s of the natural style grammar onto an SgrAttr
+// applied to the layer l.
+func naturalColor(s string, l Layer) (SgrAttr, bool) {
+	s = strings.ToLower(s)
+	offset := 0
+	if l == BG {
+		offset = 2
+	}
+	switch {
+	case s == "default":
+		if l == BG {
+			return DefaultBg, true
+		}
+		return DefaultFg, true
+	case strings.HasPrefix(s, brightPrefix):
+		c, ok := naturalColors[s[len(brightPrefix):]]
+		return c[offset+1], ok
+	case strings.HasPrefix(s, "#"):
+		c, err := ParseHex(s)
+		if err != nil {
+			return "", false
+		}
+		return Rgb24(l, c.R, c.G, c.B), true
+	case strings.HasPrefix(s, "grey"), strings.HasPrefix(s, "gray"):
+		n, ok := decimal(s[4:])
+		if !ok || n > 23 {
+			return "", false
+		}
+		return Rgb8(l, uint8(232+n)), true
+	}
+	if c, ok := naturalColors[s]; ok {
+		return c[offset], true
+	}
+	n, ok := decimal(s)
+	if !ok || !validUint8(n) {
+		return "", false
+	}
+	return Rgb8(l, uint8(n)), true
+}
+
+// HexDigit returns the value of the hexadecimal digit c.
+func hexDigit(c byte) (uint8, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// Decimal parses s made up of one to three decimal digits.
+func decimal(s string) (int, bool) {
+	if len(s) == 0 || len(s) > 3 {
+		return 0, false
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+		n = 10*n + int(s[i]-'0')
+	}
+	return n, true
+}
+
+// SuggestColors returns names of colors of the natural style grammar similar
+// to the string s.
+func suggestColors(s string) []string {
+	names := []string{"default"}
+	for name := range naturalColors {
+		names = append(names, name, brightPrefix+name)
+	}
+	return suggestFrom(s, names)
+}
+
+// FieldsOf calls fn with each whitespace-separated field of s in order. It is
+// meant to split style strings without allocating a slice of fields. It stops
+// early and returns the error returned by fn.
+func fieldsOf(s string, fn func(string) error) error {
+	for i := 0; i < len(s); {
+		if isSpace(s[i]) {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(s) && !isSpace(s[j]) {
+			j++
+		}
+		if err := fn(s[i:j]); err != nil {
+			return err
+		}
+		i = j
+	}
+	return nil
+}
+
+// IsSpace tells whether the byte c is ASCII whitespace.
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+}
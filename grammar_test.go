@@ -0,0 +1,171 @@
+package termcols
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMapColorsNatural(t *testing.T) {
+	cases := []struct {
+		styles []string
+		exp    []SgrAttr
+	}{
+		{
+			[]string{"bold bright-red on blue"},
+			[]SgrAttr{Bold, RedBfg, BlueBg},
+		},
+		{
+			[]string{"bold", "bright-red", "on", "blue"},
+			[]SgrAttr{Bold, RedBfg, BlueBg},
+		},
+		{
+			[]string{"italic #ff0 on grey23"},
+			[]SgrAttr{Italic, Rgb24(FG, 255, 255, 0), Rgb8(BG, 255)},
+		},
+		{
+			[]string{"underline fg=red bg=default"},
+			[]SgrAttr{Underline, RedFg, DefaultBg},
+		},
+		{
+			[]string{"BOLD On Bright-Cyan", "FG=#FF8700"},
+			[]SgrAttr{Bold, CyanBbg, Rgb24(FG, 255, 135, 0)},
+		},
+		{
+			[]string{"on", "#0a0b0c gray0 bg=bright-black"},
+			[]SgrAttr{Rgb24(BG, 10, 11, 12), Rgb8(FG, 232), BlackBbg},
+		},
+		{
+			[]string{"214 on 17", "default"},
+			[]SgrAttr{Rgb8(FG, 214), Rgb8(BG, 17), DefaultFg},
+		},
+		{
+			[]string{"\tstrike\n  white  on\tblack "},
+			[]SgrAttr{Strike, WhiteFg, BlackBg},
+		},
+		{
+			[]string{"bold bluefg rgb24=bg:1:2:3", "on magenta"},
+			[]SgrAttr{Bold, BlueFg, Rgb24(BG, 1, 2, 3), MagentaBg},
+		},
+		{
+			[]string{"adaptive=blue/bright-yellow"},
+			[]SgrAttr{Adaptive{BlueFg, YellowBfg}.SgrAttr()},
+		},
+	}
+	for _, c := range cases {
+		t.Run(strings.Join(c.styles, "|"), func(t *testing.T) {
+			out, err := MapColors(c.styles)
+			if err != nil {
+				t.Fatalf("Have: %v, want: nil", err)
+			}
+			if !reflect.DeepEqual(out, c.exp) {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestMapColorsNaturalFail(t *testing.T) {
+	cases := [][]string{
+		{"on"},
+		{"bold on", ""},
+		{"on bluebg"},
+		{"on on blue"},
+		{"on bright-default"},
+		{"bright-"},
+		{"bright-bright-red"},
+		{"#ff"},
+		{"#ff00ff0"},
+		{"#ggg"},
+		{"grey"},
+		{"grey-1"},
+		{"grey100"},
+		{"256"},
+		{"-1"},
+		{"fg="},
+		{"xg=red"},
+		{"fg=bluefg"},
+		{"bg=on"},
+		{"red on"},
+	}
+	for _, c := range cases {
+		t.Run(strings.Join(c, "|"), func(t *testing.T) {
+			if _, err := MapColors(c); !errors.Is(err, ErrMap) {
+				t.Errorf("Have: %v, want: %v", err, ErrMap)
+			}
+		})
+	}
+}
+
+func TestNaturalColor(t *testing.T) {
+	cases := []struct {
+		s   string
+		l   Layer
+		exp SgrAttr
+		ok  bool
+	}{
+		{"red", FG, RedFg, true},
+		{"red", BG, RedBg, true},
+		{"bright-red", FG, RedBfg, true},
+		{"bright-red", BG, RedBbg, true},
+		{"default", FG, DefaultFg, true},
+		{"default", BG, DefaultBg, true},
+		{"#f80", FG, Rgb24(FG, 255, 136, 0), true},
+		{"#FF8800", BG, Rgb24(BG, 255, 136, 0), true},
+		{"grey0", FG, Rgb8(FG, 232), true},
+		{"gray12", BG, Rgb8(BG, 244), true},
+		{"0", FG, Rgb8(FG, 0), true},
+		{"255", BG, Rgb8(BG, 255), true},
+
+		{"", FG, "", false},
+		{"purple", FG, "", false},
+		{"bright-purple", FG, "", false},
+		{"#12345", FG, "", false},
+		{"#12345x", FG, "", false},
+		{"grey24", FG, "", false},
+		{"grey123", FG, "", false},
+		{"1000", FG, "", false},
+	}
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			out, ok := naturalColor(c.s, c.l)
+			if out != c.exp || ok != c.ok {
+				t.Errorf("Have: %q %t, want: %q %t", out, ok, c.exp, c.ok)
+			}
+		})
+	}
+}
+
+func TestFieldsOf(t *testing.T) {
+	cases := []struct {
+		s   string
+		exp []string
+	}{
+		{"", nil},
+		{" \t\n", nil},
+		{"bold", []string{"bold"}},
+		{" bold  on\tblue\n", []string{"bold", "on", "blue"}},
+	}
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			var out []string
+			fieldsOf(c.s, func(f string) error {
+				out = append(out, f)
+				return nil
+			})
+			if !reflect.DeepEqual(out, c.exp) {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+		})
+	}
+	errStop := errors.New("stop")
+	var n int
+	err := fieldsOf("a b c", func(string) error {
+		n++
+		return errStop
+	})
+	if err != errStop || n != 1 {
+		t.Errorf("Have: %v after %d fields, want: %v after 1 field", err, n, errStop)
+	}
+}
@@ -0,0 +1,136 @@
+package termcols
+
+import (
+	"image"
+	"image/color"
+	"strings"
+)
+
+// Half blocks used to draw two pixels in a single terminal cell.
+const (
+	upperHalf = "▀"
+	lowerHalf = "▄"
+)
+
+// RenderImage renders the image img with upper half block characters, so that
+// each terminal cell shows two pixels stacked on top of one another with the
+// foreground and the background color. The image is resized to width
+// columns, and its height is scaled to keep the aspect ratio of the image
+// given the shape of terminal cells. Pixels of the image that are mostly
+// transparent are left in the default terminal colors.
+//
+// Colors are 24-bit colors downgraded to the profile p the same way as with
+// Profile.Convert. The Plain profile renders nothing. Each line of the result
+// ends with the reset control sequence and a line break.
+func RenderImage(img image.Image, width int, p Profile) string {
+	b := img.Bounds()
+	if p <= Plain || width <= 0 || b.Empty() {
+		return ""
+	}
+	// NOTE: Terminal cells are about twice as tall as they are wide, so a cell
+	// split into two half blocks holds two roughly square pixels, and the
+	// image keeps its aspect ratio with one pixel per half block.
+	scaled := ScaleImage(img, width, 0)
+	height := scaled.Bounds().Dy()
+
+	var s strings.Builder
+	for y := 0; y < height; y += 2 {
+		var fg, bg SgrAttr
+		for x := 0; x < width; x++ {
+			top := pixelAt(scaled, x, y)
+			bottom := pixel{}
+			if y+1 < height {
+				bottom = pixelAt(scaled, x, y+1)
+			}
+			glyph, cellFg, cellBg := " ", SgrAttr(""), SgrAttr("")
+			switch {
+			case top.opaque && bottom.opaque:
+				glyph, cellFg, cellBg = upperHalf, top.c.Fg(), bottom.c.Bg()
+			case top.opaque:
+				glyph, cellFg = upperHalf, top.c.Fg()
+			case bottom.opaque:
+				glyph, cellFg = lowerHalf, bottom.c.Fg()
+			}
+			cellFg, cellBg = p.Convert(cellFg), p.Convert(cellBg)
+			// NOTE: Colors are written only when they change from the previous
+			// cell, which keeps flat areas such as logo backgrounds small.
+			if cellFg != fg {
+				s.WriteString(string(orDefault(cellFg, Csi+"39m")))
+				fg = cellFg
+			}
+			if cellBg != bg {
+				s.WriteString(string(orDefault(cellBg, Csi+"49m")))
+				bg = cellBg
+			}
+			s.WriteString(glyph)
+		}
+		s.WriteString(string(Reset))
+		s.WriteByte('\n')
+	}
+	return s.String()
+}
+
+// Pixel is a pixel of a scaled image.
+type pixel struct {
+	c      Color
+	opaque bool
+}
+
+// PixelAt returns the pixel of the image img at x and y. Pixels that are
+// mostly transparent are not opaque.
+func pixelAt(img *image.RGBA64, x, y int) pixel {
+	c := img.RGBA64At(x, y)
+	// NOTE: Colors of an RGBA64 image are alpha-premultiplied, so they are
+	// divided by the alpha to get the color back.
+	if c.A < 0x8000 {
+		return pixel{}
+	}
+	a := uint32(c.A)
+	return pixel{
+		c:      Color{uint8(uint32(c.R) * 0xff / a), uint8(uint32(c.G) * 0xff / a), uint8(uint32(c.B) * 0xff / a)},
+		opaque: true,
+	}
+}
+
+// ScaleImage scales the image img to width by height pixels. Each pixel is
+// the average of the source pixels it covers, which keeps thin lines and small
+// details of downscaled images visible. When height is not positive, it is
+// chosen to keep the aspect ratio of the image. The scaled image is empty
+// when width is not positive or img is empty.
+func ScaleImage(img image.Image, width, height int) *image.RGBA64 {
+	b := img.Bounds()
+	if width <= 0 || b.Empty() {
+		return image.NewRGBA64(image.Rectangle{})
+	}
+	if height <= 0 {
+		height = max((2*b.Dy()*width+b.Dx())/(2*b.Dx()), 1)
+	}
+	result := image.NewRGBA64(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := b.Min.Y + y*b.Dy()/height
+		y1 := max(b.Min.Y+(y+1)*b.Dy()/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := b.Min.X + x*b.Dx()/width
+			x1 := max(b.Min.X+(x+1)*b.Dx()/width, x0+1)
+			var r, g, bl, a, n uint64
+			for py := y0; py < y1; py++ {
+				for px := x0; px < x1; px++ {
+					pr, pg, pb, pa := img.At(px, py).RGBA()
+					r, g, bl, a = r+uint64(pr), g+uint64(pg), bl+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			// NOTE: Premultiplied channels can be averaged directly.
+			result.SetRGBA64(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(bl / n), uint16(a / n)})
+		}
+	}
+	return result
+}
+
+// OrDefault returns the attribute a or def when a is empty.
+func orDefault(a, def SgrAttr) SgrAttr {
+	if a == "" {
+		return def
+	}
+	return a
+}
@@ -0,0 +1,175 @@
+package termcols
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// testImage returns a width by height image with pixels colored by fn.
+func testImage(width, height int, fn func(x, y int) color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, fn(x, y))
+		}
+	}
+	return img
+}
+
+func TestRenderImage(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	clear := color.RGBA{}
+	rows := func(x, y int) color.Color {
+		if y%2 == 0 {
+			return red
+		}
+		return blue
+	}
+	redFg, blueBg := string(Rgb24(FG, 255, 0, 0)), string(Rgb24(BG, 0, 0, 255))
+	reset := string(Reset)
+	cases := []struct {
+		name  string
+		img   image.Image
+		width int
+		p     Profile
+		exp   string
+	}{
+		{
+			"truecolor",
+			testImage(2, 2, rows),
+			2,
+			TrueColor,
+			redFg + blueBg + "▀▀" + reset + "\n",
+		},
+		{
+			"ansi256",
+			testImage(2, 2, rows),
+			2,
+			ANSI256,
+			string(Rgb8(FG, 196)) + string(Rgb8(BG, 21)) + "▀▀" + reset + "\n",
+		},
+		{
+			"ansi",
+			testImage(1, 2, rows),
+			1,
+			ANSI,
+			"\033[91m\033[44m▀" + reset + "\n",
+		},
+		{
+			"plain",
+			testImage(2, 2, rows),
+			2,
+			Plain,
+			"",
+		},
+		{
+			"downscale",
+			testImage(4, 4, func(x, y int) color.Color {
+				if y < 2 {
+					return red
+				}
+				return blue
+			}),
+			2,
+			TrueColor,
+			redFg + blueBg + "▀▀" + reset + "\n",
+		},
+		{
+			"average",
+			testImage(2, 2, func(x, y int) color.Color {
+				if x == 0 {
+					return color.RGBA{200, 0, 0, 255}
+				}
+				return color.RGBA{100, 0, 0, 255}
+			}),
+			1,
+			TrueColor,
+			string(Rgb24(FG, 150, 0, 0)) + "▀" + reset + "\n",
+		},
+		{
+			"odd-height",
+			testImage(1, 3, rows),
+			1,
+			TrueColor,
+			redFg + blueBg + "▀" + reset + "\n" + redFg + "▀" + reset + "\n",
+		},
+		{
+			"transparent",
+			testImage(3, 2, func(x, y int) color.Color {
+				switch {
+				case x == 0 && y == 0, x == 1 && y == 1:
+					return red
+				}
+				return clear
+			}),
+			3,
+			TrueColor,
+			redFg + "▀▄\033[39m " + reset + "\n",
+		},
+		{
+			"aspect",
+			testImage(4, 2, func(x, y int) color.Color { return red }),
+			2,
+			TrueColor,
+			redFg + "▀▀" + reset + "\n",
+		},
+		{
+			"upscale",
+			testImage(1, 1, func(x, y int) color.Color { return red }),
+			2,
+			TrueColor,
+			redFg + string(Rgb24(BG, 255, 0, 0)) + "▀▀" + reset + "\n",
+		},
+		{
+			"zero-width",
+			testImage(1, 1, func(x, y int) color.Color { return red }),
+			0,
+			TrueColor,
+			"",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if out := RenderImage(c.img, c.width, c.p); out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestScaleImage(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	halves := testImage(4, 2, func(x, y int) color.Color {
+		if x < 2 {
+			return red
+		}
+		return color.RGBA{}
+	})
+	cases := []struct {
+		name   string
+		img    image.Image
+		width  int
+		height int
+		size   image.Point
+		at     color.RGBA64
+	}{
+		{"aspect", halves, 2, 0, image.Pt(2, 1), color.RGBA64{0xffff, 0, 0, 0xffff}},
+		{"explicit", halves, 1, 3, image.Pt(1, 3), color.RGBA64{0x7fff, 0, 0, 0x7fff}},
+		{"rounded", testImage(3, 2, func(x, y int) color.Color { return red }), 2, 0, image.Pt(2, 1), color.RGBA64{0xffff, 0, 0, 0xffff}},
+		{"upscale", testImage(1, 1, func(x, y int) color.Color { return red }), 3, 0, image.Pt(3, 3), color.RGBA64{0xffff, 0, 0, 0xffff}},
+		{"zero-width", halves, 0, 0, image.Point{}, color.RGBA64{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := ScaleImage(c.img, c.width, c.height)
+			if size := out.Bounds().Size(); size != c.size {
+				t.Fatalf("Have: %v, want: %v", size, c.size)
+			}
+			if at := out.RGBA64At(0, 0); at != c.at {
+				t.Errorf("Have: %v, want: %v", at, c.at)
+			}
+		})
+	}
+}
@@ -2,11 +2,21 @@ package termcols
 
 import (
 	"errors"
-	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 )
 
+const (
+	// MaxNameLen is the length of the longest name in colorMap.
+	maxNameLen = 12
+
+	// MaxCached caps the number of entries held in specCache.
+	maxCached = 256
+)
+
+var specCache cache
+
 var layerMap map[string]Layer = map[string]Layer{"fg": FG, "bg": BG}
 
 var colorMap map[string]SgrAttr = map[string]SgrAttr{
@@ -39,7 +49,8 @@ var colorMap map[string]SgrAttr = map[string]SgrAttr{
 	"greenbbg": GreenBbg,
 
 	"yellowfg":  YellowFg,
-	"yellobfg":  YellowBfg,
+	"yellowbfg": YellowBfg,
+	"yellobfg":  YellowBfg, // NOTE: Misspelled name kept for compatibility.
 	"yellowbg":  YellowBg,
 	"yellowbbg": YellowBbg,
 
@@ -64,152 +75,531 @@ var colorMap map[string]SgrAttr = map[string]SgrAttr{
 	"whitebbg": WhiteBbg,
 }
 
+// NameMap maps the predefined colors and styles back onto their names. The
+// misspelled yellobfg is left out, so that each attribute has a single name.
+var nameMap = func() map[SgrAttr]string {
+	m := make(map[SgrAttr]string, len(colorMap))
+	for name, attr := range colorMap {
+		if name != "yellobfg" {
+			m[attr] = name
+		}
+	}
+	return m
+}()
+
+// NOTE: Parameters that switch attributes off have no predefined names, so
+// they are named after the attributes they switch off.
+var offNames = map[SgrAttr]string{
+	Reset:       "reset",
+	Csi + "22m": "no-bold",
+	Csi + "23m": "no-italic",
+	Csi + "24m": "no-underline",
+	Csi + "25m": "no-blink",
+	Csi + "27m": "no-reverse",
+	Csi + "28m": "no-hide",
+	Csi + "29m": "no-strike",
+}
+
 var (
 	// ErrMap indicates that there were issues with disambiguating color names.
 	ErrMap = errors.New("Color mapping error")
 )
 
 // MapColors attempts to interpret string elements of the ss slice as a set of
-// predefined colors/styles or an RGB8/24 string pattern that is expected to
-// come in one of the case-insensitive patterns listed below. Otherwise the
-// function returns an empty slice and errMap.
+// predefined colors/styles, RGB8/24 string patterns or terms of the natural
+// style grammar. Elements may hold several whitespace-separated terms, so both
+// []string{"bold", "bright-red", "on", "blue"} and
+// []string{"bold bright-red on blue"} yield Bold, RedBfg and BlueBg. Otherwise
+// the function returns an empty slice and a [*StyleError] that matches ErrMap
+// and points at the first element that could not be mapped.
+//
+// The natural style grammar is given below in EBNF. Terms are separated with
+// whitespace and are case-insensitive. A color applies to the foreground
+// unless it follows the on keyword or the bg= prefix. Gray levels index the
+// 24 steps of the grayscale ramp of the 256-color palette, from the darkest
+// grey0 to the lightest grey23, and plain numbers index the whole palette.
+// Every term accepted by [MapColor] is valid as well, so are the existing
+// spellings such as bluefg or rgb24=bg:1:2:3.
 //
-//	RGB 8  : rgb8=[fg|bg]:[0-255]
-//	RGB 24 : rgb24=[fg|bg]:[0-255]:[0-255]:[0-255]
+//	spec   = { term } .
+//	term   = style | color | "on" color | layer "=" color | legacy .
+//	style  = "bold" | "faint" | "italic" | "underline" | "blink" | "reverse"
+//	       | "hide" | "strike" | "defaultstyle" .
+//	layer  = "fg" | "bg" .
+//	color  = [ "bright-" ] name | "default" | "#" hex hex hex [ hex hex hex ]
+//	       | ( "grey" | "gray" ) level | index .
+//	name   = "black" | "red" | "green" | "yellow" | "blue" | "magenta"
+//	       | "cyan" | "white" .
+//	level  = digit [ digit ] .                   (* 0 to 23 *)
+//	index  = digit [ digit [ digit ] ] .         (* 0 to 255 *)
+//	hex    = digit | "a" | "b" | "c" | "d" | "e" | "f" .
+//	digit  = "0" | "1" | "2" | "3" | "4" | "5" | "6" | "7" | "8" | "9" .
+//	legacy = (* predefined name, RGB 8, RGB 24 or Adaptive pattern *) .
+//
+// Examples of valid specs are bold bright-red on blue, italic #ff0 on grey23
+// and underline fg=red bg=default.
 func MapColors(ss []string) ([]SgrAttr, error) {
 	result := make([]SgrAttr, 0, 3)
-	for _, s := range ss {
-		attr, err := MapColor(s)
+	var on bool
+	var onIndex int
+	for i, s := range ss {
+		var n int
+		err := fieldsOf(s, func(f string) error {
+			n++
+			if on {
+				on = false
+				attr, ok := naturalColor(f, BG)
+				if !ok {
+					return diagnoseColor(&StyleError{Token: f}, "", f)
+				}
+				result = append(result, attr)
+				return nil
+			}
+			if strings.EqualFold(f, onKeyword) {
+				on, onIndex = true, i
+				return nil
+			}
+			attr, err := MapColor(f)
+			if err != nil {
+				return err
+			}
+			result = append(result, attr)
+			return nil
+		})
+		if err == nil && n == 0 {
+			err = diagnose(s)
+		}
 		if err != nil {
-			return []SgrAttr{}, ErrMap
+			var se *StyleError
+			if errors.As(err, &se) {
+				se.Index = i
+			}
+			return []SgrAttr{}, err
 		}
-		result = append(result, attr)
+	}
+	if on {
+		e := &StyleError{Token: onKeyword, Index: onIndex, Reason: ReasonMalformed}
+		return []SgrAttr{}, e
 	}
 	return result, nil
 }
 
 // MapColor attempts to interpret the string s as either one of the predefined
-// colors/styles or an RGB8/24 string pattern that is expected to come in the
-// one of the case-insensitive patterns listed below. Otherwise the function
-// returns an empty string of type SgrAttr and errMap.
+// colors/styles, an RGB8/24 string pattern that is expected to come in the
+// one of the case-insensitive patterns listed below or a single term of the
+// natural style grammar described with [MapColors], such as bright-red or
+// bg=#ff8700. Otherwise the function returns an empty string of type SgrAttr
+// and a [*StyleError] that matches ErrMap and tells what is wrong with s.
+//
+// The adaptive pattern takes two of the other patterns, the first one for
+// terminals with a light background and the second one for terminals with a
+// dark background. It is resolved to one of the two with [Adaptive] based on
+// the background reported by [IsDarkBackground] each time it is mapped, so
+// it is never cached and follows later calls to [SetDarkBackground].
+//
+// The Okabe-Ito pattern picks one of the colors of the colorblind-safe
+// palette, such as okabeito=fg:vermillion, by one of the names taken by
+// [OkabeIto].
 //
-//	RGB 8  : rgb8=[fg|bg]:[0-255]
-//	RGB 24 : rgb24=[fg|bg]:[0-255]:[0-255]:[0-255]
+//	RGB 8     : rgb8=[fg|bg]:[0-255]
+//	RGB 24    : rgb24=[fg|bg]:[0-255]:[0-255]:[0-255]
+//	Adaptive  : adaptive=[light]/[dark]
+//	Okabe-Ito : okabeito=[fg|bg]:[name]
 func MapColor(s string) (SgrAttr, error) {
-	col, ok := colorMap[strings.ToLower(s)]
-	if ok {
+	if col, ok := lookupName(s); ok {
 		return col, nil
 	}
-	re8 := regexp.MustCompile(
-		`(?mi)^rgb8=(?P<layer>fg|bg):(?P<color>\d{1,3})$`,
-	)
-	if matchRegexp(re8, s) {
-		col, ok := collateRgb8(re8, s)
-		if !ok {
-			return "", ErrMap
-		}
-		return col, nil
+	if light, dark, ok := splitAdaptive(s); ok {
+		return collateAdaptive(s, light, dark)
 	}
-	re24 := regexp.MustCompile(
-		`(?mi)^rgb24=(?P<layer>fg|bg):(?P<r>\d{1,3}):(?P<g>\d{1,3}):(?P<b>\d{1,3})$`,
-	)
-	if matchRegexp(re24, s) {
-		col, ok := collateRgb24(re24, s)
-		if !ok {
-			return "", ErrMap
-		}
+	if col, ok := specCache.get(s); ok {
 		return col, nil
 	}
-	return "", ErrMap
-}
-
-// MatchRegexp checks if val matches the provided regex r.
-func matchRegexp(r *regexp.Regexp, val any) bool {
-	valStr, ok := val.(string)
+	col, ok := scanRgb(s)
 	if !ok {
-		return false
+		col, ok = scanOkabeIto(s)
 	}
-	return r.MatchString(valStr)
+	if !ok {
+		col, ok = naturalTerm(s)
+	}
+	if !ok {
+		return "", diagnose(s)
+	}
+	specCache.put(s, col)
+	return col, nil
 }
 
-// CollateRgb8 parses string s into SgrAttr using the provided regex r.
-func collateRgb8(r *regexp.Regexp, s string) (SgrAttr, bool) {
-	params := getParams(r, s)
-	l, ok := getLayer(params)
+// Name returns the name that MapColor maps onto the attribute a, which is
+// either one of the predefined names, such as bold or bluefg, or the RGB8/24
+// pattern of an 8-bit or 24-bit color, such as rgb24=bg:57:124:12. It returns
+// false when a is not a single attribute produced by MapColor.
+func Name(a SgrAttr) (string, bool) {
+	if name, ok := nameMap[a]; ok {
+		return name, true
+	}
+	params, ok := sgrParams(a)
 	if !ok {
 		return "", false
 	}
-	c, ok := getColor(params, "color")
-	if !ok {
+	p := splitParams(params)
+	var layer string
+	switch p[0] {
+	case 38:
+		layer = "fg"
+	case 48:
+		layer = "bg"
+	default:
 		return "", false
 	}
-	result := Rgb8(l, c)
-	return result, true
+	for _, v := range p[1:] {
+		if !validUint8(v) {
+			return "", false
+		}
+	}
+	switch {
+	case len(p) == 3 && p[1] == 5:
+		return "rgb8=" + layer + ":" + strconv.Itoa(p[2]), true
+	case len(p) == 5 && p[1] == 2:
+		return "rgb24=" + layer + ":" + strconv.Itoa(p[2]) + ":" + strconv.Itoa(p[3]) + ":" + strconv.Itoa(p[4]), true
+	}
+	return "", false
 }
 
-// CollateRgb24 parses string s into SgrAttr using the provided regex r.
-func collateRgb24(r *regexp.Regexp, s string) (SgrAttr, bool) {
-	params := getParams(r, s)
-	l, ok := getLayer(params)
-	if !ok {
+// OffName returns the name of the attribute a switching display attributes
+// off, such as no-bold for CSI 22m, or reset for the Reset attribute. It
+// returns false for any other attribute, including the ones named by Name.
+func OffName(a SgrAttr) (string, bool) {
+	name, ok := offNames[a]
+	return name, ok
+}
+
+// LookupName finds the predefined color or style named s regardless of its
+// case. The lowercase copy of s is kept on the stack so that the lookup does
+// not allocate.
+func lookupName(s string) (SgrAttr, bool) {
+	var buf [maxNameLen]byte
+	if len(s) > len(buf) {
 		return "", false
 	}
-	red, ok := getColor(params, "r")
-	if !ok {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		buf[i] = c
+	}
+	col, ok := colorMap[string(buf[:len(s)])]
+	return col, ok
+}
+
+// ScanRgb parses the RGB8/24 pattern s into SgrAttr.
+func scanRgb(s string) (SgrAttr, bool) {
+	sc := scanner{s: s}
+	var n int
+	switch {
+	case sc.prefix("rgb8="):
+		n = 1
+	case sc.prefix("rgb24="):
+		n = 3
+	default:
 		return "", false
 	}
-	green, ok := getColor(params, "g")
-	if !ok {
+	var l Layer
+	switch {
+	case sc.prefix("fg"):
+		l = FG
+	case sc.prefix("bg"):
+		l = BG
+	default:
 		return "", false
 	}
-	blue, ok := getColor(params, "b")
+	var c [3]uint8
+	for i := 0; i < n; i++ {
+		if !sc.prefix(":") {
+			return "", false
+		}
+		v, ok := sc.uint8()
+		if !ok {
+			return "", false
+		}
+		c[i] = v
+	}
+	if !sc.done() {
+		return "", false
+	}
+	if n == 1 {
+		return Rgb8(l, c[0]), true
+	}
+	return Rgb24(l, c[0], c[1], c[2]), true
+}
+
+// ScanOkabeIto parses the Okabe-Ito pattern s into SgrAttr.
+func scanOkabeIto(s string) (SgrAttr, bool) {
+	sc := scanner{s: s}
+	if !sc.prefix("okabeito=") {
+		return "", false
+	}
+	var l Layer
+	switch {
+	case sc.prefix("fg:"):
+		l = FG
+	case sc.prefix("bg:"):
+		l = BG
+	default:
+		return "", false
+	}
+	c, ok := okabeIto[strings.ToLower(sc.s[sc.pos:])]
 	if !ok {
 		return "", false
 	}
-	result := Rgb24(l, red, green, blue)
-	return result, true
+	return Rgb24(l, c.R, c.G, c.B), true
+}
+
+// Scanner walks over the string s one token at a time.
+type scanner struct {
+	s   string
+	pos int
+}
+
+// Prefix consumes the lowercase string p if the remaining part of the
+// scanned string starts with it regardless of its case.
+func (sc *scanner) prefix(p string) bool {
+	rest := sc.s[sc.pos:]
+	if len(rest) < len(p) {
+		return false
+	}
+	for i := 0; i < len(p); i++ {
+		c := rest[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		if c != p[i] {
+			return false
+		}
+	}
+	sc.pos += len(p)
+	return true
 }
 
-// GetParams extracts regex named capturing group names and values.
-func getParams(r *regexp.Regexp, s string) map[string]string {
-	match := r.FindStringSubmatch(s)
-	result := make(map[string]string)
-	for i, name := range r.SubexpNames() {
-		if i > 0 && i <= len(match) {
-			result[name] = match[i]
+// Uint8 consumes a decimal number of one to three digits in range [0, 255].
+func (sc *scanner) uint8() (uint8, bool) {
+	v, digits := 0, 0
+	for sc.pos < len(sc.s) && digits < 3 {
+		c := sc.s[sc.pos]
+		if c < '0' || c > '9' {
+			break
 		}
+		v = 10*v + int(c-'0')
+		sc.pos++
+		digits++
 	}
-	return result
+	if digits == 0 || !validUint8(v) {
+		return 0, false
+	}
+	return uint8(v), true
 }
 
-// GetLayer returns Layer based on the key in the params map.
-func getLayer(params map[string]string) (Layer, bool) {
-	lr, ok := params["layer"]
-	if !ok {
-		return "", false
+// Done reports whether the whole string has been consumed.
+func (sc *scanner) done() bool {
+	return sc.pos == len(sc.s)
+}
+
+// Cache keeps SgrAttr values of RGB patterns and natural grammar terms that
+// have already been parsed.
+// It is emptied whenever it grows past maxCached entries to bound its size.
+type cache struct {
+	sync.Mutex
+	m map[string]SgrAttr
+}
+
+func (c *cache) get(s string) (SgrAttr, bool) {
+	c.Lock()
+	defer c.Unlock()
+	col, ok := c.m[s]
+	return col, ok
+}
+
+func (c *cache) put(s string, col SgrAttr) {
+	c.Lock()
+	defer c.Unlock()
+	if c.m == nil || len(c.m) >= maxCached {
+		c.m = make(map[string]SgrAttr)
+	}
+	// NOTE: s is cloned so that the cache does not pin a larger string s
+	// might be a slice of, e.g., a whole line of input.
+	c.m[strings.Clone(s)] = col
+}
+
+// SplitAdaptive splits the adaptive pattern s into light and dark patterns.
+func splitAdaptive(s string) (string, string, bool) {
+	const prefix = "adaptive="
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return "", "", false
 	}
-	lr = strings.ToLower(lr)
-	l, ok := layerMap[lr]
+	light, dark, ok := strings.Cut(s[len(prefix):], "/")
 	if !ok {
-		return "", false
+		return "", "", false
 	}
-	return l, true
+	return light, dark, true
 }
 
-// GetColor returns the color based on the key in the params map.
-func getColor(params map[string]string, key string) (uint8, bool) {
-	val, ok := params[key]
-	if !ok {
-		return 0, false
+// CollateAdaptive maps light and dark patterns of the adaptive pattern s and
+// picks one of the two.
+func collateAdaptive(s, light, dark string) (SgrAttr, error) {
+	l, err := MapColor(light)
+	if err != nil {
+		return "", adaptiveError(s, err, func(v string) string { return v + "/" + dark })
 	}
-	col, err := strconv.Atoi(val)
+	d, err := MapColor(dark)
 	if err != nil {
-		return 0, false
+		return "", adaptiveError(s, err, func(v string) string { return light + "/" + v })
 	}
-	if ok := validUint8(col); !ok {
-		return 0, false
+	return Adaptive{Light: l, Dark: d}.SgrAttr(), nil
+}
+
+// AdaptiveError turns the error err of one of the patterns of the adaptive
+// pattern s into the error of s. The patch function puts suggestions for the
+// offending pattern back into s.
+func adaptiveError(s string, err error, patch func(string) string) error {
+	var se *StyleError
+	if !errors.As(err, &se) {
+		return err
 	}
-	return uint8(col), true
+	e := &StyleError{Token: s, Reason: se.Reason}
+	prefix := s[:strings.Index(s, "=")+1]
+	for _, sug := range se.Suggestions {
+		e.Suggestions = append(e.Suggestions, prefix+patch(sug))
+	}
+	return e
+}
+
+// Diagnose tells why the string s could not be mapped onto an SgrAttr.
+func diagnose(s string) *StyleError {
+	e := &StyleError{Token: s}
+	name, args, _ := strings.Cut(s, "=")
+	var want int
+	switch strings.ToLower(name) {
+	case "rgb8":
+		want = 1
+	case "rgb24":
+		want = 3
+	case "adaptive":
+		e.Reason = ReasonMalformed
+		return e
+	case "okabeito":
+		return diagnoseOkabeIto(e, s[:len(name)+1], args)
+	case "fg", "bg":
+		return diagnoseColor(e, s[:len(name)+1], args)
+	default:
+		if isNaturalColor(s) {
+			return diagnoseColor(e, "", s)
+		}
+		e.Reason = ReasonUnknownName
+		e.Suggestions = suggestNames(s)
+		if e.Suggestions == nil {
+			e.Suggestions = suggestColors(s)
+		}
+		return e
+	}
+	layer, rest, ok := strings.Cut(args, ":")
+	vals := strings.Split(rest, ":")
+	if !ok || len(vals) != want {
+		e.Reason = ReasonMalformed
+		return e
+	}
+	nums := make([]int, len(vals))
+	for i, v := range vals {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || strings.ContainsAny(v, "+-") {
+			e.Reason = ReasonMalformed
+			return e
+		}
+		nums[i] = n
+	}
+	if _, ok := layerMap[strings.ToLower(layer)]; !ok {
+		e.Reason = ReasonBadLayer
+		e.Suggestions = suggestLayers(name+"=", layer, ":"+rest)
+		return e
+	}
+	for _, n := range nums {
+		if !validUint8(n) {
+			e.Reason = ReasonOutOfRange
+			return e
+		}
+	}
+	// NOTE: Numbers with leading zeros such as 0255 exceed the three digits
+	// allowed by the pattern.
+	e.Reason = ReasonMalformed
+	return e
+}
+
+// DiagnoseColor fills in the error e telling why the color c of the natural
+// style grammar preceded by prefix could not be mapped onto an SgrAttr.
+func diagnoseColor(e *StyleError, prefix, c string) *StyleError {
+	lc := strings.ToLower(c)
+	switch {
+	case strings.HasPrefix(lc, "#"):
+		e.Reason = ReasonMalformed
+		return e
+	case strings.HasPrefix(lc, "grey"), strings.HasPrefix(lc, "gray"):
+		if _, ok := decimal(lc[4:]); ok {
+			e.Reason = ReasonMalformed
+			return e
+		}
+	}
+	if n, err := strconv.Atoi(c); err == nil && n >= 0 {
+		e.Reason = ReasonMalformed
+		if !validUint8(n) {
+			e.Reason = ReasonOutOfRange
+		}
+		return e
+	}
+	e.Reason = ReasonUnknownName
+	for _, sug := range suggestColors(c) {
+		e.Suggestions = append(e.Suggestions, prefix+sug)
+	}
+	return e
+}
+
+// DiagnoseOkabeIto fills in the error e telling why the arguments args of the
+// Okabe-Ito pattern preceded by prefix could not be mapped onto an SgrAttr.
+func diagnoseOkabeIto(e *StyleError, prefix, args string) *StyleError {
+	layer, name, ok := strings.Cut(args, ":")
+	if !ok {
+		e.Reason = ReasonMalformed
+		return e
+	}
+	if _, ok := layerMap[strings.ToLower(layer)]; !ok {
+		e.Reason = ReasonBadLayer
+		e.Suggestions = suggestLayers(prefix, layer, ":"+name)
+		return e
+	}
+	names := make([]string, 0, len(okabeIto))
+	for n := range okabeIto {
+		names = append(names, n)
+	}
+	e.Reason = ReasonUnknownName
+	for _, sug := range suggestFrom(name, names) {
+		e.Suggestions = append(e.Suggestions, prefix+layer+":"+sug)
+	}
+	return e
+}
+
+// IsNaturalColor tells whether s looks like a color of the natural style
+// grammar rather than a predefined name.
+func isNaturalColor(s string) bool {
+	s = strings.ToLower(s)
+	if strings.HasPrefix(s, "#") || strings.HasPrefix(s, brightPrefix) {
+		return true
+	}
+	if _, err := strconv.Atoi(s); err == nil {
+		return true
+	}
+	if strings.HasPrefix(s, "grey") || strings.HasPrefix(s, "gray") {
+		_, ok := decimal(s[4:])
+		return ok
+	}
+	return false
 }
 
 // ValidUint8 verifies if the integer i falls in range [0, 255] of uint8.
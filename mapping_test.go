@@ -1,9 +1,8 @@
 package termcols
 
 import (
+	"errors"
 	"fmt"
-	"reflect"
-	"regexp"
 	"strings"
 	"testing"
 )
@@ -26,7 +25,7 @@ func TestMapColors(t *testing.T) {
 	}
 	for _, c := range cases {
 		t.Run(strings.Join(c.colors, "-"), func(t *testing.T) {
-			if _, out := MapColors(c.colors); out != c.expOut {
+			if _, out := MapColors(c.colors); !errors.Is(out, c.expOut) {
 				t.Errorf("Have: %t, want %t", out, c.expOut)
 			}
 		})
@@ -70,6 +69,13 @@ func TestMapColor(t *testing.T) {
 		{"RGB24=bg:123:22:40", nil},
 		{"rgb24=fg:0:12:255", nil},
 
+		// Okabe-Ito patterns
+		{"okabeito=fg:orange", nil},
+		{"OkabeIto=BG:ReddishPurple", nil},
+		{"okabeito=fg:red", ErrMap},
+		{"okabeito=gb:blue", ErrMap},
+		{"okabeito=fg", ErrMap},
+
 		// Failing RGB patterns
 		{"", ErrMap},                         // empty string
 		{"rgb24", ErrMap},                    // missing parameters
@@ -84,206 +90,207 @@ func TestMapColor(t *testing.T) {
 	}
 	for _, c := range cases {
 		t.Run(c.color, func(t *testing.T) {
-			if _, out := MapColor(c.color); out != c.expOut {
+			if _, out := MapColor(c.color); !errors.Is(out, c.expOut) {
 				t.Errorf("Have: %t, want %t", out, c.expOut)
 			}
 		})
 	}
 }
 
-func TestMatchRegexp(t *testing.T) {
+func TestLookupName(t *testing.T) {
 	cases := []struct {
-		re    *regexp.Regexp
-		s     interface{}
-		okExp bool
+		s   string
+		exp SgrAttr
+		ok  bool
 	}{
-		{
-			regexp.MustCompile(
-				`(?mi)^rgb8=(?P<layer>fg|bg):(?P<color>\d{1,3})$`,
-			),
-			"rgb8=fg:255",
-			true,
-		},
-		{
-			regexp.MustCompile(
-				`(?mi)^rgb24=(?P<layer>fg|bg):(?P<r>\d{1,3}):(?P<g>\d{1,3}):(?P<b>\d{1,3})$`,
-			),
-			"rgb24=fg:255:255:255",
-			true,
-		},
-		{regexp.MustCompile(`rgb:.*`), "RGB24", false},
-		{regexp.MustCompile(`.*`), struct{}{}, false},
-		{regexp.MustCompile(`.*`), 10_000, false},
-		{regexp.MustCompile(`.*`), 3.14, false},
+		{"bold", Bold, true},
+		{"MagentaBBG", MagentaBbg, true},
+		{"DEFAULTSTYLE", DefaultStyle, true},
+		{"", "", false},
+		{"bolder", "", false},
+		{"defaultstyles", "", false}, // longer than any name
 	}
 	for _, c := range cases {
-		t.Run(fmt.Sprintf("%v", c.s), func(t *testing.T) {
-			if ok := matchRegexp(c.re, c.s); ok != c.okExp {
-				t.Errorf("Have: %t; want %t", ok, c.okExp)
+		t.Run(c.s, func(t *testing.T) {
+			out, ok := lookupName(c.s)
+			if out != c.exp || ok != c.ok {
+				t.Errorf("Have: %q %t, want: %q %t", out, ok, c.exp, c.ok)
 			}
 		})
 	}
 }
 
-func TestCollateRgb8(t *testing.T) {
-	re := regexp.MustCompile(
-		`(?mi)^rgb8=(?P<layer>fg|bg):(?P<color>\d{1,3})$`,
-	)
+func TestName(t *testing.T) {
 	cases := []struct {
-		re    *regexp.Regexp
-		str   string
-		okExp bool
+		a   SgrAttr
+		exp string
+		ok  bool
 	}{
-		{re, "rgb8=fg:126", true},
-		{re, "", false},
-		{re, "rgb8=222", false},
-		{re, "rgb8=gb:255", false},
-		{re, "rgb8=fg:", false},
-		{re, "rgb8=bg:black", false},
-		{re, "rgb8=fg:256", false},
+		{Bold, "bold", true},
+		{BlueFg, "bluefg", true},
+		{YellowBfg, "yellowbfg", true},
+		{DefaultBg, "defaultbg", true},
+		{Rgb8(FG, 196), "rgb8=fg:196", true},
+		{Rgb24(BG, 57, 124, 12), "rgb24=bg:57:124:12", true},
+		{Reset, "", false},
+		{Combine(Bold, BlueFg), "", false},
+		{Csi + "38;5;256m", "", false},
+		{Csi + "48;2;1;2m", "", false},
+		{"bold", "", false},
+	}
+	for _, c := range cases {
+		t.Run(c.exp, func(t *testing.T) {
+			out, ok := Name(c.a)
+			if out != c.exp || ok != c.ok {
+				t.Errorf("Have: %q %t, want: %q %t", out, ok, c.exp, c.ok)
+			}
+		})
+	}
+}
 
-		// NOTE: Made-up regular expressions to trigger collation errors
-		{regexp.MustCompile(`.*`), "", false}, // missing layer field
-		{
-			regexp.MustCompile(`rgb8=(?P<layer>gb):(?P<color>red)`),
-			"rgb8=gb:red",
-			false,
-		}, // `gb` is not a valid layer on the layerMap
-		{
-			regexp.MustCompile(`rgb8=(?P<layer>fg|bg):(?P<color>red)`),
-			"rgb8=fg:red",
-			false,
-		}, // red cannot be converted to an int
-		{
-			regexp.MustCompile(`rgb8=(?P<layer>bg):.*`),
-			"rgb8=bg:missing",
-			false,
-		}, // missing color field
+func TestOffName(t *testing.T) {
+	cases := []struct {
+		a   SgrAttr
+		exp string
+		ok  bool
+	}{
+		{Reset, "reset", true},
+		{Csi + "22m", "no-bold", true},
+		{Csi + "29m", "no-strike", true},
+		{Bold, "", false},
+		{DefaultFg, "", false},
+		{Csi + "22;23m", "", false},
 	}
 	for _, c := range cases {
-		t.Run(c.str, func(t *testing.T) {
-			if _, ok := collateRgb8(c.re, c.str); ok != c.okExp {
-				t.Errorf("Have: %t; want %t", ok, c.okExp)
+		t.Run(string(c.a), func(t *testing.T) {
+			out, ok := OffName(c.a)
+			if out != c.exp || ok != c.ok {
+				t.Errorf("Have: %q %t, want: %q %t", out, ok, c.exp, c.ok)
 			}
 		})
 	}
 }
 
-func TestCollateRgb24(t *testing.T) {
-	re := regexp.MustCompile(
-		`(?mi)^rgb24=(?P<layer>fg|bg):(?P<r>\d{1,3}):(?P<g>\d{1,3}):(?P<b>\d{1,3})$`,
-	)
+// Test that names of all predefined attributes map back onto them.
+func TestNameRoundTrip(t *testing.T) {
+	for _, a := range colorMap {
+		name, ok := Name(a)
+		if !ok {
+			t.Fatalf("Have: no name for %q", a)
+		}
+		if out, err := MapColor(name); err != nil || out != a {
+			t.Errorf("Have: %q %v, want: %q", out, err, a)
+		}
+	}
+}
+
+func TestMaxNameLen(t *testing.T) {
+	for name := range colorMap {
+		if len(name) > maxNameLen {
+			t.Errorf("Have: %q of length %d, want: at most %d", name, len(name), maxNameLen)
+		}
+	}
+}
+
+func TestLookupNameAllocs(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		benchAttr, _ = MapColor("MagentaBfg")
+	})
+	if allocs != 0 {
+		t.Errorf("Have: %.0f allocs, want: 0", allocs)
+	}
+}
+
+func TestScanRgb(t *testing.T) {
 	cases := []struct {
-		re    *regexp.Regexp
-		str   string
-		okExp bool
+		s   string
+		exp SgrAttr
+		ok  bool
 	}{
-		{re, "rgb24=fg:126:12:56", true},
-		{re, "", false},
-		{re, "rgb24=222:232:101", false},
-		{re, "rgb24=gb:255:255:255", false},
-		{re, "rgb24=fg:", false},
-		{re, "rgb24=fg:312:120:2", false},
-		{re, "rgb24=fg:120:120:257", false},
+		{"rgb8=fg:126", Rgb8(FG, 126), true},
+		{"RGB8=BG:0", Rgb8(BG, 0), true},
+		{"rgb8=bg:007", Rgb8(BG, 7), true},
+		{"rgb24=fg:126:12:56", Rgb24(FG, 126, 12, 56), true},
+		{"Rgb24=Bg:255:255:255", Rgb24(BG, 255, 255, 255), true},
 
-		// NOTE: Made-up regular expressions to trigger collation errors
-		{regexp.MustCompile(`.*`), "", false}, // missing layer field
-		{
-			regexp.MustCompile(`rgb24=(?P<layer>gb):.*`),
-			"rgb24=gb:red",
-			false,
-		}, // `gb` is not a valid layer on the layerMap
-		{
-			regexp.MustCompile(
-				`rgb24=(?P<layer>fg|bg):(?P<r>red)`,
-			),
-			"rgb24=fg:red",
-			false,
-		}, //  red cannot be converted to an int
-		{
-			regexp.MustCompile(
-				`rgb24=(?P<layer>fg|bg):(?P<g>green)`,
-			),
-			"rgb24=fg:green",
-			false,
-		}, // green cannot be converted to an int
-		{
-			regexp.MustCompile(
-				`rgb24=(?P<layer>fg|bg):(?P<b>blue)`,
-			),
-			"rgb24=fg:blue",
-			false,
-		}, // blue cannot be converted to an int
-		{
-			regexp.MustCompile(`rgb24=(?P<layer>bg):.*`),
-			"rgb24=bg:255:255:255",
-			false,
-		}, // missing r field
-		{
-			regexp.MustCompile(`rgb24=(?P<layer>bg):(?P<r>\d{1,3}):.*`),
-			"rgb24=bg:255:255:255",
-			false,
-		}, // missing g field
-		{
-			regexp.MustCompile(
-				`rgb24=(?P<layer>bg):(?P<r>\d{1,3}):(?P<g>\d{1,3}):.*`,
-			),
-			"rgb24=bg:255:255:255",
-			false,
-		}, // missing b field
-		{
-			regexp.MustCompile(`rgb24=(?P<layer>bg):(?P<r>red):.*`),
-			"rgb24=bg:red:255:255",
-			false,
-		}, // red cannot be converted to an int
-		{
-			regexp.MustCompile(
-				`rgb24=(?P<layer>bg):(?P<r>\d{1,3}):(?P<g>green):.*`,
-			),
-			"rgb24=bg:255:green:255",
-			false,
-		}, // green cannot be converted to an int
-		{
-			regexp.MustCompile(
-				`rgb24=(?P<layer>bg):(?P<r>\d{1,3}):(?P<g>\d{1,3}):(?P<b>blue)`,
-			),
-			"rgb24=bg:255:255:blue",
-			false,
-		}, // blue cannot be converted to an int
+		{"", "", false},
+		{"rgb8", "", false},
+		{"rgb8=222", "", false},
+		{"rgb8=gb:255", "", false},
+		{"rgb8=fg:", "", false},
+		{"rgb8=fg:-1", "", false},
+		{"rgb8=fg:+1", "", false},
+		{"rgb8=bg:black", "", false},
+		{"rgb8=fg:256", "", false},
+		{"rgb8=fg:0255", "", false},
+		{"rgb8=fg:12 ", "", false},
+		{"rgb8=fg:12\nbold", "", false},
+		{"rgb8=fg:12:12", "", false},
+		{"rgb24=222:232:101", "", false},
+		{"rgb24=gb:255:255:255", "", false},
+		{"rgb24=fg:", "", false},
+		{"rgb24=fg:12:12", "", false},
+		{"rgb24=fg:12::12", "", false},
+		{"rgb24=fg:312:120:2", "", false},
+		{"rgb24=fg:120:120:257", "", false},
+		{"rgb24=fg:1:2:3:4", "", false},
 	}
 	for _, c := range cases {
-		t.Run(c.str, func(t *testing.T) {
-			if _, ok := collateRgb24(c.re, c.str); ok != c.okExp {
-				t.Errorf("Have: %t; want %t", ok, c.okExp)
+		t.Run(c.s, func(t *testing.T) {
+			out, ok := scanRgb(c.s)
+			if out != c.exp || ok != c.ok {
+				t.Errorf("Have: %q %t, want: %q %t", out, ok, c.exp, c.ok)
 			}
 		})
 	}
 }
 
-func TestGetParams(t *testing.T) {
-	re := regexp.MustCompile(
-		`(?mi)^rgb8=(?P<layer>fg|bg):(?P<color>\d{1,3})$`,
-	)
+func TestScanOkabeIto(t *testing.T) {
 	cases := []struct {
-		re     *regexp.Regexp
-		str    string
-		outExp map[string]string
+		s   string
+		exp SgrAttr
+		ok  bool
 	}{
-		{re, "rgb8=fg:254", map[string]string{"layer": "fg", "color": "254"}},
-		{re, "rgb8=bg:121", map[string]string{"layer": "bg", "color": "121"}},
-		{re, "RGB8=FG:101", map[string]string{"layer": "FG", "color": "101"}},
-		{re, "RGB8=fg:142", map[string]string{"layer": "fg", "color": "142"}},
-		{re, "rgb8=BG:236", map[string]string{"layer": "BG", "color": "236"}},
+		{"okabeito=fg:orange", Rgb24(FG, 230, 159, 0), true},
+		{"OKABEITO=bg:SkyBlue", Rgb24(BG, 86, 180, 233), true},
+		{"okabeito=bg:vermillion", Rgb24(BG, 213, 94, 0), true},
+
+		{"", "", false},
+		{"okabeito=", "", false},
+		{"okabeito=fg", "", false},
+		{"okabeito=fg:", "", false},
+		{"okabeito=xg:blue", "", false},
+		{"okabeito=fg:purple", "", false},
+		{"okabeito:fg:blue", "", false},
 	}
 	for _, c := range cases {
-		t.Run(c.str, func(t *testing.T) {
-			out := getParams(c.re, c.str)
-			if !reflect.DeepEqual(c.outExp, out) {
-				t.Errorf("Have %v; want %v", out, c.outExp)
+		t.Run(c.s, func(t *testing.T) {
+			out, ok := scanOkabeIto(c.s)
+			if out != c.exp || ok != c.ok {
+				t.Errorf("Have: %q %t, want: %q %t", out, ok, c.exp, c.ok)
 			}
 		})
 	}
+}
 
+func TestCache(t *testing.T) {
+	var c cache
+	if _, ok := c.get("rgb8=fg:1"); ok {
+		t.Fatal("Have: hit on empty cache, want: miss")
+	}
+	for i := 0; i < maxCached; i++ {
+		c.put(fmt.Sprintf("rgb8=fg:%d", i), Rgb8(FG, uint8(i)))
+	}
+	if out, ok := c.get("rgb8=fg:12"); !ok || out != Rgb8(FG, 12) {
+		t.Errorf("Have: %q %t, want: %q %t", out, ok, Rgb8(FG, 12), true)
+	}
+	c.put("rgb8=bg:1", Rgb8(BG, 1))
+	if len(c.m) > maxCached {
+		t.Errorf("Have: %d entries, want: at most %d", len(c.m), maxCached)
+	}
+	if out, ok := c.get("rgb8=bg:1"); !ok || out != Rgb8(BG, 1) {
+		t.Errorf("Have: %q %t, want: %q %t", out, ok, Rgb8(BG, 1), true)
+	}
 }
 
 func TestValidUint(t *testing.T) {
@@ -315,51 +322,92 @@ func TestValidUint(t *testing.T) {
 	}
 }
 
-func TestGetLayer(t *testing.T) {
+func TestMapColorAdaptive(t *testing.T) {
 	cases := []struct {
-		name   string
-		params map[string]string
-		okExp  bool
+		color string
+		dark  bool
+		exp   SgrAttr
+		err   error
 	}{
-		{"missing-param", map[string]string{"color": "redfg"}, false},
-		{"empty-params", map[string]string{}, false},
-		{"unknown-layer", map[string]string{"layer": "background"}, false},
-		{"layer-bg", map[string]string{"layer": "bg"}, true},
-		{"layer-fg", map[string]string{"layer": "fg"}, true},
+		{"adaptive=bluefg/yellowfg", true, YellowFg, nil},
+		{"adaptive=bluefg/yellowfg", false, BlueFg, nil},
+		{"ADAPTIVE=rgb8=bg:230/rgb24=bg:0:43:54", true, Rgb24(BG, 0, 43, 54), nil},
+		{"adaptive=rgb8=bg:230/rgb24=bg:0:43:54", false, Rgb8(BG, 230), nil},
+
+		{"adaptive=bluefg", true, "", ErrMap},   // missing dark pattern
+		{"adaptive=bluefg/", true, "", ErrMap},  // empty dark pattern
+		{"adaptive=/bluefg", false, "", ErrMap}, // empty light pattern
+		{"adaptive=blue/bright-yellow", true, YellowBfg, nil},
+		{"adaptive=bluefg/yelow", true, "", ErrMap},          // unknown dark color
+		{"adaptive=rgb8=fg:256/bold", false, "", ErrMap},     // invalid light color
+		{"adaptive:bluefg/yellowfg", false, "", ErrMap},      // unknown pattern
+		{"adaptive=bold/italic/underline", true, "", ErrMap}, // too many patterns
 	}
 	for _, c := range cases {
-		t.Run("", func(t *testing.T) {
-			if _, ok := getLayer(c.params); ok != c.okExp {
-				t.Errorf("Have %t; want %t", ok, c.okExp)
+		t.Run(c.color, func(t *testing.T) {
+			// NOTE: The pattern is mapped for the other background first to
+			// show that the result is not cached.
+			SetDarkBackground(!c.dark)
+			MapColor(c.color)
+			SetDarkBackground(c.dark)
+			out, err := MapColor(c.color)
+			if !errors.Is(err, c.err) {
+				t.Errorf("Have: %v, want: %v", err, c.err)
+			}
+			if out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
 			}
 		})
 	}
 }
 
-func TestGetColor(t *testing.T) {
-	cases := []struct {
-		name   string
-		params map[string]string
-		key    string
-		okExp  bool
-	}{
-		{"empty-parms", map[string]string{}, "color", false},
-		{"mismatched-key-color", map[string]string{"r": "48"}, "color", false},
-		{"mismatched-key-r", map[string]string{"g": "248"}, "r", false},
-		{"mismatched-key-g", map[string]string{"b": "143"}, "g", false},
-		{"mismatched-key-b", map[string]string{"r": "0"}, "b", false},
-		{"color-not-int", map[string]string{"r": "red"}, "r", false},
-		{"invalid-uint8", map[string]string{"color": "278"}, "color", false},
-		{"valid-color", map[string]string{"color": "12"}, "color", true},
-		{"valid-r", map[string]string{"r": "234"}, "r", true},
-		{"valid-g", map[string]string{"g": "197"}, "g", true},
-		{"valid-b", map[string]string{"b": "65"}, "b", true},
+// NOTE: benchAttr is added to avoid compiler optimization
+var benchAttr SgrAttr
+
+func BenchmarkMapColorNamed(b *testing.B) {
+	b.ReportAllocs()
+	var a SgrAttr
+	for i := 0; i < b.N; i++ {
+		a, _ = MapColor("MagentaBfg")
 	}
-	for _, c := range cases {
-		t.Run(c.name, func(t *testing.T) {
-			if _, ok := getColor(c.params, c.key); ok != c.okExp {
-				t.Errorf("Have: %t; want %t", ok, c.okExp)
-			}
-		})
+	benchAttr = a
+}
+
+func BenchmarkMapColorRgb8(b *testing.B) {
+	b.ReportAllocs()
+	var a SgrAttr
+	for i := 0; i < b.N; i++ {
+		a, _ = MapColor("rgb8=fg:214")
+	}
+	benchAttr = a
+}
+
+func BenchmarkMapColorRgb24(b *testing.B) {
+	b.ReportAllocs()
+	var a SgrAttr
+	for i := 0; i < b.N; i++ {
+		a, _ = MapColor("rgb24=bg:12:128:255")
+	}
+	benchAttr = a
+}
+
+func BenchmarkMapColors(b *testing.B) {
+	b.ReportAllocs()
+	styles := []string{"bold", "rgb8=fg:214", "rgb24=bg:12:128:255"}
+	var as []SgrAttr
+	for i := 0; i < b.N; i++ {
+		as, _ = MapColors(styles)
+	}
+	if len(as) > 0 {
+		benchAttr = as[0]
+	}
+}
+
+func BenchmarkScanRgb24(b *testing.B) {
+	b.ReportAllocs()
+	var a SgrAttr
+	for i := 0; i < b.N; i++ {
+		a, _ = scanRgb("rgb24=bg:12:128:255")
 	}
+	benchAttr = a
 }
@@ -0,0 +1,188 @@
+package progress
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mdm-code/termcols"
+)
+
+// Default look of progress bars
+const (
+	DefaultWidth = 30
+	DefaultFill  = "█"
+	DefaultEmpty = "░"
+)
+
+// Bar is a progress bar of a task with a known total amount of work. It is
+// safe for concurrent use, so many goroutines can report their progress on
+// the same bar. The exported fields configure the look of the bar and should
+// be set before the bar is drawn for the first time.
+type Bar struct {
+	Label string
+
+	// Width is the number of terminal cells taken up by the bar itself.
+	Width int
+
+	// Fill and Empty are the strings repeated in the done and the remaining
+	// part of the bar, each one taking up a single terminal cell.
+	Fill  string
+	Empty string
+
+	// FillStyle and EmptyStyle are attributes of the two parts of the bar.
+	FillStyle  []termcols.SgrAttr
+	EmptyStyle []termcols.SgrAttr
+
+	// Gradient, when it has at least two colors, colors the done part of
+	// the bar with 24-bit foreground colors blended across its whole width
+	// and takes precedence over FillStyle. The colors are downgraded to the
+	// color profile the bar is rendered with.
+	Gradient []termcols.Color
+
+	mu      sync.Mutex
+	total   int64
+	current int64
+	start   time.Time
+}
+
+// NewBar returns a Bar with the given label and the total amount of work
+// drawn with the default look. The time of the call is taken as the start of
+// the task, which is used to estimate the rate and the remaining time.
+func NewBar(label string, total int64) *Bar {
+	return &Bar{
+		Label: label,
+		Width: DefaultWidth,
+		Fill:  DefaultFill,
+		Empty: DefaultEmpty,
+		total: total,
+		start: time.Now(),
+	}
+}
+
+// Add adds n to the amount of work done.
+func (b *Bar) Add(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current = min(max(b.current+n, 0), b.total)
+}
+
+// Set sets the amount of work done to n.
+func (b *Bar) Set(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current = min(max(n, 0), b.total)
+}
+
+// SetTotal changes the total amount of work, for instance when more work is
+// discovered along the way.
+func (b *Bar) SetTotal(total int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.total = max(total, 0)
+	b.current = min(b.current, b.total)
+}
+
+// Current returns the amount of work done and the total amount of work.
+func (b *Bar) Current() (int64, int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.current, b.total
+}
+
+// Done reports whether all the work has been done.
+func (b *Bar) Done() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.current >= b.total
+}
+
+// Render returns the line of the bar at the time now: the label, the bar, the
+// percentage, the amount of work done, the rate in units per second and the
+// estimated remaining time. Attributes and colors are downgraded to the color
+// profile of the renderer r, and with the Plain profile, the line has no
+// control sequences and the bar is drawn with Fill and Empty alone.
+func (b *Bar) Render(now time.Time, r *termcols.Renderer) string {
+	b.mu.Lock()
+	current, total, start := b.current, b.total, b.start
+	b.mu.Unlock()
+
+	ratio := 1.0
+	if total > 0 {
+		ratio = float64(current) / float64(total)
+	}
+	width := max(b.Width, 0)
+	filled := int(ratio * float64(width))
+
+	var s strings.Builder
+	if b.Label != "" {
+		s.WriteString(b.Label)
+		s.WriteByte(' ')
+	}
+	s.WriteString(b.fill(filled, width, r))
+	s.WriteString(b.empty(width-filled, r))
+	fmt.Fprintf(&s, " %3d%% %d/%d", int(ratio*100), current, total)
+
+	elapsed := now.Sub(start)
+	if elapsed <= 0 || current == 0 {
+		return s.String()
+	}
+	rate := float64(current) / elapsed.Seconds()
+	s.WriteByte(' ')
+	s.WriteString(formatRate(rate))
+	if current < total {
+		eta := time.Duration(float64(total-current) / rate * float64(time.Second))
+		s.WriteString(" ETA ")
+		s.WriteString(eta.Round(time.Second).String())
+	}
+	return s.String()
+}
+
+// Fill returns the done part of the bar n cells wide out of width.
+func (b *Bar) fill(n, width int, r *termcols.Renderer) string {
+	if r.Profile() <= termcols.Plain {
+		return strings.Repeat(b.Fill, n)
+	}
+	if len(b.Gradient) < 2 {
+		return r.Colorize(strings.Repeat(b.Fill, n), b.FillStyle...)
+	}
+	if n == 0 {
+		return ""
+	}
+	var s strings.Builder
+	for i := 0; i < n; i++ {
+		s.WriteString(string(r.Profile().Convert(gradientAt(b.Gradient, i, width).Fg())))
+		s.WriteString(b.Fill)
+	}
+	s.WriteString(string(termcols.Reset))
+	return s.String()
+}
+
+// Empty returns the remaining part of the bar n cells wide.
+func (b *Bar) empty(n int, r *termcols.Renderer) string {
+	return r.Colorize(strings.Repeat(b.Empty, n), b.EmptyStyle...)
+}
+
+// GradientAt returns the color of the cell i out of width cells of a bar
+// blending the colors of stops evenly spread across its width.
+func gradientAt(stops []termcols.Color, i, width int) termcols.Color {
+	if width <= 1 {
+		return stops[0]
+	}
+	pos := float64(i) / float64(width-1) * float64(len(stops)-1)
+	j := min(int(pos), len(stops)-2)
+	return stops[j].Mix(stops[j+1], pos-float64(j))
+}
+
+// FormatRate formats the rate given in units per second.
+func formatRate(rate float64) string {
+	switch {
+	case rate >= 100:
+		return strconv.FormatFloat(rate, 'f', 0, 64) + "/s"
+	case rate >= 1:
+		return strconv.FormatFloat(rate, 'f', 1, 64) + "/s"
+	}
+	return strconv.FormatFloat(rate*60, 'f', 1, 64) + "/min"
+}
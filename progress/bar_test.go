@@ -0,0 +1,156 @@
+package progress
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mdm-code/termcols"
+)
+
+func TestBarRender(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		label   string
+		current int64
+		total   int64
+		elapsed time.Duration
+		exp     string
+	}{
+		{"start", "copy", 0, 100, 0, "copy ░░░░░░░░░░   0% 0/100"},
+		{"half", "copy", 50, 100, 10 * time.Second, "copy █████░░░░░  50% 50/100 5.0/s ETA 10s"},
+		{"done", "copy", 100, 100, 4 * time.Second, "copy ██████████ 100% 100/100 25.0/s"},
+		{"no-label", "", 1, 3, time.Second, "███░░░░░░░  33% 1/3 1.0/s ETA 2s"},
+		{"slow", "", 1, 10, time.Minute, "█░░░░░░░░░  10% 1/10 1.0/min ETA 9m0s"},
+		{"fast", "", 500, 1000, time.Second, "█████░░░░░  50% 500/1000 500/s ETA 1s"},
+		{"empty", "", 0, 0, time.Second, "██████████ 100% 0/0"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := NewBar(c.label, c.total)
+			b.Width = 10
+			b.start = start
+			b.Set(c.current)
+			if out := b.Render(start.Add(c.elapsed), renderer(termcols.Plain)); out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestBarStyles(t *testing.T) {
+	b := NewBar("", 4)
+	b.Width, b.Fill, b.Empty = 4, "=", "-"
+	b.FillStyle = []termcols.SgrAttr{termcols.GreenFg}
+	b.EmptyStyle = []termcols.SgrAttr{termcols.Faint}
+	b.Set(1)
+	exp := termcols.Colorize("=", termcols.GreenFg) + termcols.Colorize("---", termcols.Faint)
+	if out := b.Render(b.start, renderer(termcols.TrueColor)); !strings.HasPrefix(out, exp+" ") {
+		t.Errorf("Have: %q, want prefix: %q", out, exp)
+	}
+	if out := b.Render(b.start, renderer(termcols.Plain)); !strings.HasPrefix(out, "=--- ") {
+		t.Errorf("Have: %q, want prefix: %q", out, "=--- ")
+	}
+}
+
+func TestBarGradient(t *testing.T) {
+	red, blue := termcols.Color{R: 255}, termcols.Color{B: 255}
+	b := NewBar("", 3)
+	b.Width, b.Fill, b.Empty = 3, "=", "-"
+	b.Gradient = []termcols.Color{red, blue}
+	b.Set(3)
+	exp := string(red.Fg()) + "=" + string(red.Mix(blue, 0.5).Fg()) + "=" + string(blue.Fg()) + "=" + string(termcols.Reset)
+	if out := b.Render(b.start, renderer(termcols.TrueColor)); !strings.HasPrefix(out, exp+" ") {
+		t.Errorf("Have: %q, want prefix: %q", out, exp)
+	}
+	b.Set(0)
+	if out := b.Render(b.start, renderer(termcols.TrueColor)); !strings.HasPrefix(out, "--- ") {
+		t.Errorf("Have: %q, want prefix: %q", out, "--- ")
+	}
+}
+
+func TestBarProfile(t *testing.T) {
+	orange := termcols.Color{R: 255, G: 135}
+	b := NewBar("", 2)
+	b.Width, b.Fill, b.Empty = 2, "=", "-"
+	b.EmptyStyle = []termcols.SgrAttr{termcols.Rgb24(termcols.FG, 255, 135, 0)}
+	b.Gradient = []termcols.Color{orange, orange}
+	b.Set(1)
+	cases := []struct {
+		profile termcols.Profile
+		exp     string
+	}{
+		{termcols.Plain, "=-"},
+		{termcols.ANSI256, string(termcols.Rgb8(termcols.FG, 208)) + "=" + string(termcols.Reset) +
+			termcols.Colorize("-", termcols.Rgb8(termcols.FG, 208))},
+		{termcols.TrueColor, string(orange.Fg()) + "=" + string(termcols.Reset) +
+			termcols.Colorize("-", orange.Fg())},
+	}
+	for _, c := range cases {
+		t.Run(c.profile.String(), func(t *testing.T) {
+			if out := b.Render(b.start, renderer(c.profile)); !strings.HasPrefix(out, c.exp+" ") {
+				t.Errorf("Have: %q, want prefix: %q", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestGradientAt(t *testing.T) {
+	stops := []termcols.Color{{R: 255}, {G: 255}, {B: 255}}
+	cases := []struct {
+		i, width int
+		exp      termcols.Color
+	}{
+		{0, 5, stops[0]},
+		{2, 5, stops[1]},
+		{4, 5, stops[2]},
+		{0, 1, stops[0]},
+	}
+	for _, c := range cases {
+		if out := gradientAt(stops, c.i, c.width); out != c.exp {
+			t.Errorf("Have: %v, want: %v", out, c.exp)
+		}
+	}
+}
+
+func TestBarUpdates(t *testing.T) {
+	b := NewBar("", 10)
+	b.Add(4)
+	b.Add(-6)
+	if cur, _ := b.Current(); cur != 0 {
+		t.Errorf("Have: %d, want: 0", cur)
+	}
+	b.Set(20)
+	if !b.Done() {
+		t.Error("Have: not done, want: done")
+	}
+	b.SetTotal(30)
+	if cur, total := b.Current(); cur != 10 || total != 30 || b.Done() {
+		t.Errorf("Have: %d/%d, want: 10/30", cur, total)
+	}
+	b.SetTotal(5)
+	if cur, total := b.Current(); cur != 5 || total != 5 {
+		t.Errorf("Have: %d/%d, want: 5/5", cur, total)
+	}
+}
+
+func TestBarConcurrent(t *testing.T) {
+	b := NewBar("", 1000)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.Add(1)
+				b.Render(time.Now(), renderer(termcols.TrueColor))
+			}
+		}()
+	}
+	wg.Wait()
+	if cur, _ := b.Current(); cur != 1000 {
+		t.Errorf("Have: %d, want: 1000", cur)
+	}
+}
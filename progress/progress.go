@@ -0,0 +1,237 @@
+/*
+Package progress draws progress bars and spinners on the terminal. Any number
+of bars and spinners can be shown at the same time, and they can be updated
+concurrently from many goroutines.
+
+On terminals, a Progress redraws all its bars and spinners in place using
+the control sequences of the cursor package. When the output is not a
+terminal, such as a log file or a CI job, it degrades to writing plain lines
+without any control sequences at a much lower rate.
+
+Bars are styled with termcols SGR attributes, and the done part of a bar can
+be colored with a gradient of 24-bit colors. Items are drawn with a
+termcols.Renderer, so attributes and colors turn into 8-bit or ANSI colors
+where 24-bit colors are not supported and are left out under NO_COLOR. Each bar shows the rate of work and the estimated
+remaining time.
+
+# Usage
+
+	package main
+
+	import (
+		"os"
+		"sync"
+		"time"
+
+		"github.com/mdm-code/termcols"
+		"github.com/mdm-code/termcols/progress"
+	)
+
+	func main() {
+		p := progress.New(os.Stderr)
+		var wg sync.WaitGroup
+		for _, name := range []string{"alpha", "beta"} {
+			bar := p.AddBar(name, 100)
+			bar.Gradient = []termcols.Color{{R: 255, G: 95}, {R: 95, G: 215, B: 95}}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					time.Sleep(20 * time.Millisecond)
+					bar.Add(1)
+				}
+			}()
+		}
+		p.Start()
+		wg.Wait()
+		p.Stop()
+	}
+*/
+package progress
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mdm-code/termcols"
+	"github.com/mdm-code/termcols/cursor"
+	"golang.org/x/term"
+)
+
+// Default redraw intervals
+const (
+	DefaultRefresh      = 100 * time.Millisecond
+	DefaultPlainRefresh = 5 * time.Second
+)
+
+// Item is a line drawn by a Progress, such as a Bar or a Spinner.
+type Item interface {
+	// Render returns the line of the item at the time now colorized with the
+	// renderer r, without control sequences for the Plain profile.
+	Render(now time.Time, r *termcols.Renderer) string
+}
+
+// Progress draws a list of bars, spinners and other items to a writer. It is
+// safe for concurrent use. The exported fields configure it and should be set
+// before Start is called.
+type Progress struct {
+	// Refresh is the interval between redraws on terminals.
+	Refresh time.Duration
+
+	// PlainRefresh is the interval between plain lines written when the
+	// output is not a terminal.
+	PlainRefresh time.Duration
+
+	w      io.Writer
+	r      *termcols.Renderer
+	isTerm bool
+	width  func() int
+	now    func() time.Time
+
+	mu    sync.Mutex
+	items []Item
+	lines int
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// New returns a Progress drawing to w. It redraws its items in place when w
+// is a terminal and writes plain lines otherwise. The items are colorized
+// with a termcols.Renderer for w.
+func New(w io.Writer) *Progress {
+	p := &Progress{
+		Refresh:      DefaultRefresh,
+		PlainRefresh: DefaultPlainRefresh,
+		w:            w,
+		r:            termcols.NewRenderer(w),
+		width:        func() int { return 0 },
+		now:          time.Now,
+	}
+	if f, ok := w.(interface{ Fd() uintptr }); ok && term.IsTerminal(int(f.Fd())) {
+		fd := int(f.Fd())
+		p.isTerm = true
+		p.width = func() int {
+			width, _, err := term.GetSize(fd)
+			if err != nil {
+				return 0
+			}
+			return width
+		}
+	}
+	return p
+}
+
+// Renderer returns the renderer colorizing the items, whose color profile can
+// be changed before Start is called.
+func (p *Progress) Renderer() *termcols.Renderer {
+	return p.r
+}
+
+// Add appends the item to the items drawn by the progress.
+func (p *Progress) Add(item Item) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.items = append(p.items, item)
+}
+
+// AddBar appends a new Bar with the given label and total to the progress
+// and returns it.
+func (p *Progress) AddBar(label string, total int64) *Bar {
+	b := NewBar(label, total)
+	p.Add(b)
+	return b
+}
+
+// AddSpinner appends a new Spinner with the given label to the progress and
+// returns it.
+func (p *Progress) AddSpinner(label string) *Spinner {
+	s := NewSpinner(label)
+	p.Add(s)
+	return s
+}
+
+// Start starts redrawing the items in a separate goroutine until Stop is
+// called. Calling Start on a progress that is already running does nothing.
+func (p *Progress) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stop != nil {
+		return
+	}
+	p.stop, p.done = make(chan struct{}), make(chan struct{})
+	if p.isTerm {
+		io.WriteString(p.w, string(cursor.Hide))
+	}
+	go p.loop(p.stop, p.done)
+}
+
+// Stop stops redrawing the items and draws them one last time. On terminals,
+// the cursor is left below the last item.
+func (p *Progress) Stop() {
+	p.mu.Lock()
+	stop, done := p.stop, p.done
+	p.stop, p.done = nil, nil
+	p.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+	p.Draw()
+	if p.isTerm {
+		io.WriteString(p.w, string(cursor.Show))
+	}
+}
+
+// Draw draws all the items right away. On terminals, the previously drawn
+// lines are replaced.
+func (p *Progress) Draw() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.now()
+	var b strings.Builder
+	if p.isTerm && p.lines > 0 {
+		b.WriteString(string(cursor.PrevLine(uint(p.lines))))
+	}
+	width := p.width()
+	for _, item := range p.items {
+		line := item.Render(now, p.r)
+		if p.isTerm {
+			// NOTE: Lines wrapped by the terminal would throw off the number
+			// of lines to move up on the next redraw.
+			if width > 0 {
+				line = termcols.Truncate(line, width-1, "")
+			}
+			b.WriteString(string(cursor.EraseLine(cursor.EraseAll)))
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	p.lines = len(p.items)
+	_, err := io.WriteString(p.w, b.String())
+	return err
+}
+
+// Loop redraws the items at the refresh interval until stop is closed.
+func (p *Progress) loop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	interval := p.Refresh
+	if !p.isTerm {
+		interval = p.PlainRefresh
+	}
+	if interval <= 0 {
+		interval = DefaultRefresh
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+			p.Draw()
+		}
+	}
+}
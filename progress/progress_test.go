@@ -0,0 +1,139 @@
+package progress
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mdm-code/termcols"
+	"github.com/mdm-code/termcols/cursor"
+)
+
+// item is an Item rendering a fixed line.
+type item string
+
+func (i item) Render(_ time.Time, r *termcols.Renderer) string {
+	return string(i) + "@" + r.Profile().String()
+}
+
+// Renderer returns a renderer with the color profile p.
+func renderer(p termcols.Profile) *termcols.Renderer {
+	r := termcols.NewRenderer(io.Discard)
+	r.SetProfile(p)
+	return r
+}
+
+// syncBuilder is a strings.Builder safe for concurrent use.
+type syncBuilder struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (s *syncBuilder) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuilder) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
+
+func TestDrawPlain(t *testing.T) {
+	var b strings.Builder
+	p := New(&b)
+	p.Add(item("one"))
+	p.Add(item("two"))
+	p.Draw()
+	p.Draw()
+	if out, exp := b.String(), "one@plain\ntwo@plain\none@plain\ntwo@plain\n"; out != exp {
+		t.Errorf("Have: %q, want: %q", out, exp)
+	}
+}
+
+func TestDrawTerm(t *testing.T) {
+	var b strings.Builder
+	p := New(&b)
+	p.isTerm = true
+	p.Renderer().SetProfile(termcols.ANSI)
+	p.width = func() int { return 10 }
+	p.Add(item("one"))
+	p.Add(item("a long line"))
+	p.Draw()
+	p.Draw()
+	erase := string(cursor.EraseLine(cursor.EraseAll))
+	frame := erase + "one@ansi\n" + erase + "a long li\n"
+	exp := frame + string(cursor.PrevLine(2)) + frame
+	if out := b.String(); out != exp {
+		t.Errorf("Have: %q, want: %q", out, exp)
+	}
+}
+
+func TestNewProfile(t *testing.T) {
+	cases := []struct {
+		name    string
+		noColor string
+		exp     termcols.Profile
+	}{
+		{"forced", "", termcols.ANSI256},
+		{"no-color", "1", termcols.Plain},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv("CLICOLOR_FORCE", "1")
+			t.Setenv("NO_COLOR", c.noColor)
+			t.Setenv("COLORTERM", "")
+			t.Setenv("TERM", "xterm-256color")
+			if out := New(&strings.Builder{}).Renderer().Profile(); out != c.exp {
+				t.Errorf("Have: %v, want: %v", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	var b syncBuilder
+	p := New(&b)
+	p.isTerm = true
+	p.Refresh = time.Millisecond
+	bar := p.AddBar("", 10)
+	bar.Width = 2
+	p.AddSpinner("wait").Finish()
+	p.Start()
+	p.Start()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bar.Add(1)
+		}()
+	}
+	wg.Wait()
+	time.Sleep(5 * time.Millisecond)
+	p.Stop()
+	p.Stop()
+	out := b.String()
+	if !strings.HasPrefix(out, string(cursor.Hide)) || !strings.HasSuffix(out, string(cursor.Show)) {
+		t.Errorf("Have: %q, want cursor hidden and shown", out)
+	}
+	last := out[strings.LastIndex(out, string(cursor.PrevLine(2))):]
+	if !strings.Contains(last, "100% 10/10") || !strings.Contains(last, "✓ wait") {
+		t.Errorf("Have: %q, want the final state", last)
+	}
+}
+
+func TestStopPlain(t *testing.T) {
+	var b syncBuilder
+	p := New(&b)
+	p.AddBar("copy", 2).Set(2)
+	p.Start()
+	p.Stop()
+	if out := b.String(); strings.Contains(out, "\033") || !strings.HasPrefix(out, "copy ") {
+		t.Errorf("Have: %q, want a plain line", out)
+	}
+}
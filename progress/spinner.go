@@ -0,0 +1,88 @@
+package progress
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mdm-code/termcols"
+)
+
+// Default look of spinners
+const (
+	DefaultInterval  = 80 * time.Millisecond
+	DefaultDoneFrame = "✓"
+)
+
+// DefaultFrames are the frames of a spinner drawn with Braille patterns.
+var DefaultFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Spinner shows that a task of an unknown length is running. Its frame
+// changes with time, so it keeps spinning for as long as it is redrawn. It is
+// safe for concurrent use. The exported fields configure the look of the
+// spinner and should be set before it is drawn for the first time.
+type Spinner struct {
+	Label string
+
+	// Frames are drawn one after another, each one for Interval.
+	Frames   []string
+	Interval time.Duration
+
+	// DoneFrame replaces the frames once the task is done.
+	DoneFrame string
+
+	// Style holds attributes of the frames.
+	Style []termcols.SgrAttr
+
+	mu    sync.Mutex
+	start time.Time
+	done  bool
+}
+
+// NewSpinner returns a Spinner with the given label drawn with the default
+// look. It starts spinning at the time of the call.
+func NewSpinner(label string) *Spinner {
+	return &Spinner{
+		Label:     label,
+		Frames:    DefaultFrames,
+		Interval:  DefaultInterval,
+		DoneFrame: DefaultDoneFrame,
+		start:     time.Now(),
+	}
+}
+
+// Finish marks the task of the spinner as done.
+func (s *Spinner) Finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done = true
+}
+
+// Done reports whether the task of the spinner is done.
+func (s *Spinner) Done() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.done
+}
+
+// Render returns the line of the spinner at the time now: the current frame
+// followed by the label. The style is downgraded to the color profile of the
+// renderer r, so with the Plain profile, the line has no control sequences.
+func (s *Spinner) Render(now time.Time, r *termcols.Renderer) string {
+	s.mu.Lock()
+	start, done := s.start, s.done
+	s.mu.Unlock()
+
+	frame := s.DoneFrame
+	if !done && len(s.Frames) > 0 {
+		i := 0
+		if s.Interval > 0 && now.After(start) {
+			i = int(now.Sub(start)/s.Interval) % len(s.Frames)
+		}
+		frame = s.Frames[i]
+	}
+	frame = r.Colorize(frame, s.Style...)
+	if s.Label == "" {
+		return frame
+	}
+	return frame + " " + s.Label
+}
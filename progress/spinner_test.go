@@ -0,0 +1,45 @@
+package progress
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mdm-code/termcols"
+)
+
+func TestSpinnerRender(t *testing.T) {
+	cases := []struct {
+		name    string
+		label   string
+		elapsed time.Duration
+		done    bool
+		profile termcols.Profile
+		exp     string
+	}{
+		{"start", "wait", 0, false, termcols.Plain, "a wait"},
+		{"second", "wait", 100 * time.Millisecond, false, termcols.Plain, "b wait"},
+		{"wrap", "wait", 450 * time.Millisecond, false, termcols.Plain, "b wait"},
+		{"done", "wait", time.Second, true, termcols.Plain, "ok wait"},
+		{"no-label", "", 0, false, termcols.Plain, "a"},
+		{"styled", "wait", 0, false, termcols.ANSI, termcols.Colorize("a", termcols.Bold) + " wait"},
+		{"before-start", "wait", -time.Second, false, termcols.Plain, "a wait"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := NewSpinner(c.label)
+			s.Frames = []string{"a", "b", "c"}
+			s.Interval = 100 * time.Millisecond
+			s.DoneFrame = "ok"
+			s.Style = []termcols.SgrAttr{termcols.Bold}
+			if c.done {
+				s.Finish()
+			}
+			if out := s.Render(s.start.Add(c.elapsed), renderer(c.profile)); out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+			if s.Done() != c.done {
+				t.Errorf("Have: %t, want: %t", s.Done(), c.done)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package termcols
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// QueryTimeout is the time IsDarkBackground waits for the terminal to reply.
+const QueryTimeout = 100 * time.Millisecond
+
+// Device Attributes (DA1) request sent along with each query. All terminals
+// reply to it, so the query does not have to wait for the timeout on
+// terminals that do not support OSC color queries.
+const da1 = Csi + "c"
+
+var (
+	// ErrQuery indicates that the terminal did not answer a color query.
+	ErrQuery = errors.New("Terminal query error")
+
+	background struct {
+		sync.Mutex
+		known bool
+		dark  bool
+	}
+)
+
+// QueryColors asks the terminal attached to the file f about its default
+// foreground and background colors using the OSC 10 and OSC 11 control
+// sequences. The file is put into raw mode for the time of the query. The
+// function returns ErrQuery when the terminal does not reply within timeout
+// or does not support the query.
+func QueryColors(f *os.File, timeout time.Duration) (fg, bg Color, err error) {
+	replies, err := query(f, timeout, Osc+"10;?"+St, Osc+"11;?"+St)
+	if err != nil {
+		return Color{}, Color{}, err
+	}
+	fg, ok := replies["10"]
+	if !ok {
+		return Color{}, Color{}, ErrQuery
+	}
+	bg, ok = replies["11"]
+	if !ok {
+		return Color{}, Color{}, ErrQuery
+	}
+	return fg, bg, nil
+}
+
+// QueryPalette asks the terminal attached to the file f about the colors of
+// the palette entries with the given indices using the OSC 4 control sequence.
+// The colors are returned in the same order as indices. The function returns
+// ErrQuery when the terminal does not reply within timeout or does not report
+// some of the colors.
+func QueryPalette(f *os.File, timeout time.Duration, indices ...uint8) ([]Color, error) {
+	reqs := make([]string, len(indices))
+	for i, idx := range indices {
+		reqs[i] = fmt.Sprintf("%s4;%d;?%s", Osc, idx, St)
+	}
+	replies, err := query(f, timeout, reqs...)
+	if err != nil {
+		return []Color{}, err
+	}
+	result := make([]Color, len(indices))
+	for i, idx := range indices {
+		c, ok := replies["4;"+strconv.Itoa(int(idx))]
+		if !ok {
+			return []Color{}, ErrQuery
+		}
+		result[i] = c
+	}
+	return result, nil
+}
+
+// IsDarkBackground reports whether the background of the controlling terminal
+// is dark. Unless it has been configured with SetDarkBackground, the terminal
+// is queried only once and the answer is reused in subsequent calls. When the
+// terminal cannot be queried, the function falls back onto the COLORFGBG
+// environment variable set by some terminals, and eventually assumes that the
+// background is dark, since it is the most common setup.
+func IsDarkBackground() bool {
+	background.Lock()
+	defer background.Unlock()
+	if !background.known {
+		background.dark = detectDarkBackground()
+		background.known = true
+	}
+	return background.dark
+}
+
+// SetDarkBackground overrides background detection, so that
+// IsDarkBackground reports dark from now on. It is meant for programs that let
+// their users pick the light or the dark theme explicitly.
+func SetDarkBackground(dark bool) {
+	background.Lock()
+	defer background.Unlock()
+	background.dark = dark
+	background.known = true
+}
+
+// DetectDarkBackground queries the controlling terminal about its background.
+func detectDarkBackground() bool {
+	f, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err == nil {
+		defer f.Close()
+		if _, bg, err := QueryColors(f, QueryTimeout); err == nil {
+			return isDark(bg)
+		}
+	}
+	if dark, ok := colorFgBgDark(os.Getenv("COLORFGBG")); ok {
+		return dark
+	}
+	return true
+}
+
+// ColorFgBgDark interprets the value of the COLORFGBG environment variable of
+// the form fg;bg or fg;default;bg, where bg is an index of the 16 ANSI colors.
+func colorFgBgDark(v string) (bool, bool) {
+	fields := strings.Split(v, ";")
+	bg, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || bg < 0 || bg > 15 {
+		return false, false
+	}
+	return bg <= 6 || bg == 8, true
+}
+
+// Query sends OSC requests reqs to the terminal f followed by a DA1 request
+// and collects the replies up until the DA1 reply arrives. Replies are keyed
+// with their OSC parameters preceding the color specification, for example
+// "11" or "4;1".
+func query(f *os.File, timeout time.Duration, reqs ...string) (map[string]Color, error) {
+	// NOTE: The file descriptor is obtained through SyscallConn, because
+	// calling Fd switches the file to blocking mode, which disables read
+	// deadlines.
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQuery, err)
+	}
+	var fd int
+	rc.Control(func(u uintptr) { fd = int(u) })
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("%w: not a terminal", ErrQuery)
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQuery, err)
+	}
+	defer term.Restore(fd, state)
+
+	if err := f.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQuery, err)
+	}
+	defer f.SetReadDeadline(time.Time{})
+
+	if _, err := f.WriteString(strings.Join(reqs, "") + da1); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQuery, err)
+	}
+
+	replies := make(map[string]Color)
+	var buf []byte
+	chunk := make([]byte, 256)
+	for {
+		n, err := f.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		var done bool
+		buf, done = parseReplies(buf, replies)
+		if done {
+			return replies, nil
+		}
+		if err != nil {
+			if len(replies) > 0 {
+				return replies, nil
+			}
+			return nil, fmt.Errorf("%w: %v", ErrQuery, err)
+		}
+	}
+}
+
+// ParseReplies consumes complete OSC color replies from buf and stores them in
+// replies. It returns the unconsumed remainder of buf and whether the DA1
+// reply terminating the query was found.
+func parseReplies(buf []byte, replies map[string]Color) ([]byte, bool) {
+	for {
+		i := bytes.IndexByte(buf, Esc[0])
+		if i < 0 {
+			return buf[:0], false
+		}
+		buf = buf[i:]
+		if len(buf) < 2 {
+			return buf, false
+		}
+		switch buf[1] {
+		case ']':
+			body, n := oscBody(buf)
+			if n == 0 {
+				return buf, false
+			}
+			if key, c, ok := parseReply(body); ok {
+				replies[key] = c
+			}
+			buf = buf[n:]
+		case '[':
+			end := bytes.IndexFunc(buf[2:], func(r rune) bool {
+				return r >= 0x40 && r <= 0x7e
+			})
+			if end < 0 {
+				return buf, false
+			}
+			if buf[2+end] == 'c' && bytes.HasPrefix(buf[2:], []byte("?")) {
+				return buf[:0], true
+			}
+			buf = buf[2+end+1:]
+		default:
+			buf = buf[1:]
+		}
+	}
+}
+
+// OscBody returns the body of the OSC control sequence at the beginning of buf
+// along with the total length of the sequence. The returned length is 0 if
+// the sequence is incomplete. Both BEL and ST terminators are accepted.
+func oscBody(buf []byte) (string, int) {
+	for i := 2; i < len(buf); i++ {
+		switch {
+		case buf[i] == '\a':
+			return string(buf[2:i]), i + 1
+		case buf[i] == Esc[0] && i+1 < len(buf) && buf[i+1] == '\\':
+			return string(buf[2:i]), i + 2
+		}
+	}
+	return "", 0
+}
+
+// ParseReply splits the OSC reply body of the form 11;rgb:ffff/ffff/ffff
+// into its key and the color.
+func parseReply(body string) (string, Color, bool) {
+	i := strings.LastIndexByte(body, ';')
+	if i < 0 {
+		return "", Color{}, false
+	}
+	c, ok := parseColorSpec(body[i+1:])
+	if !ok {
+		return "", Color{}, false
+	}
+	return body[:i], c, true
+}
+
+// ParseColorSpec interprets the X11 color specification of the form
+// rgb:r/g/b, with each channel of 1 to 4 hex digits, or #rgb with 1 to 4 hex
+// digits per channel.
+func parseColorSpec(s string) (Color, bool) {
+	var chans []string
+	switch {
+	case strings.HasPrefix(s, "rgb:"):
+		chans = strings.Split(s[len("rgb:"):], "/")
+	case strings.HasPrefix(s, "#"):
+		hex := s[1:]
+		if len(hex) == 0 || len(hex)%3 != 0 {
+			return Color{}, false
+		}
+		n := len(hex) / 3
+		chans = []string{hex[:n], hex[n : 2*n], hex[2*n:]}
+	default:
+		return Color{}, false
+	}
+	if len(chans) != 3 {
+		return Color{}, false
+	}
+	var rgb [3]uint8
+	for i, ch := range chans {
+		if len(ch) < 1 || len(ch) > 4 {
+			return Color{}, false
+		}
+		v, err := strconv.ParseUint(ch, 16, 16)
+		if err != nil {
+			return Color{}, false
+		}
+		max := uint64(1)<<(4*len(ch)) - 1
+		rgb[i] = uint8((v*255 + max/2) / max)
+	}
+	return Color{rgb[0], rgb[1], rgb[2]}, true
+}
@@ -0,0 +1,121 @@
+package termcols
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPty opens a pseudo-terminal pair. The slave end plays the role of the
+// terminal file handed over to the query functions while the master end acts
+// as the terminal emulator.
+func openPty(t *testing.T) (master, slave *os.File) {
+	t.Helper()
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("pseudo-terminals unavailable: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+	if err := unix.IoctlSetPointerInt(int(master.Fd()), unix.TIOCSPTLCK, 0); err != nil {
+		t.Skipf("pseudo-terminals unavailable: %v", err)
+	}
+	n, err := unix.IoctlGetInt(int(master.Fd()), unix.TIOCGPTN)
+	if err != nil {
+		t.Skipf("pseudo-terminals unavailable: %v", err)
+	}
+	slave, err = os.OpenFile("/dev/pts/"+strconv.Itoa(n), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("pseudo-terminals unavailable: %v", err)
+	}
+	t.Cleanup(func() { slave.Close() })
+	return master, slave
+}
+
+// emulate reads requests from the master end of the pseudo-terminal until the
+// DA1 request arrives and answers with the reply when it is not empty.
+func emulate(master *os.File, reply string) <-chan []byte {
+	reqs := make(chan []byte, 1)
+	go func() {
+		var buf []byte
+		chunk := make([]byte, 256)
+		for !bytes.HasSuffix(buf, []byte(da1)) {
+			n, err := master.Read(chunk)
+			if err != nil {
+				break
+			}
+			buf = append(buf, chunk[:n]...)
+		}
+		if reply != "" {
+			master.WriteString(reply)
+		}
+		reqs <- buf
+	}()
+	return reqs
+}
+
+func TestQueryColors(t *testing.T) {
+	master, slave := openPty(t)
+	reqs := emulate(
+		master,
+		"\033]10;rgb:6565/7b7b/8383\033\\\033]11;rgb:fdfd/f6f6/e3e3\033\\\033[?62;22c",
+	)
+	fg, bg, err := QueryColors(slave, time.Second)
+	if err != nil {
+		t.Fatalf("Have: %v, want: nil", err)
+	}
+	if want, have := "\033]10;?\033\\\033]11;?\033\\\033[c", string(<-reqs); have != want {
+		t.Errorf("Have: %q, want: %q", have, want)
+	}
+	if want := (Color{0x65, 0x7b, 0x83}); fg != want {
+		t.Errorf("Have: %v, want: %v", fg, want)
+	}
+	if want := (Color{0xfd, 0xf6, 0xe3}); bg != want {
+		t.Errorf("Have: %v, want: %v", bg, want)
+	}
+	if isDark(bg) {
+		t.Errorf("Have: dark, want: light")
+	}
+}
+
+func TestQueryPalette(t *testing.T) {
+	master, slave := openPty(t)
+	emulate(
+		master,
+		"\033]4;1;rgb:cdcd/0000/0000\a\033]4;12;rgb:5c5c/5c5c/ffff\a\033[?62c",
+	)
+	out, err := QueryPalette(slave, time.Second, 1, 12)
+	if err != nil {
+		t.Fatalf("Have: %v, want: nil", err)
+	}
+	if want := []Color{{205, 0, 0}, {92, 92, 255}}; !reflect.DeepEqual(out, want) {
+		t.Errorf("Have: %v, want: %v", out, want)
+	}
+}
+
+func TestQueryUnsupported(t *testing.T) {
+	master, slave := openPty(t)
+	emulate(master, "\033[?1;2c")
+	start := time.Now()
+	_, _, err := QueryColors(slave, 5*time.Second)
+	if !errors.Is(err, ErrQuery) {
+		t.Errorf("Have: %v, want: %v", err, ErrQuery)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("Query waited for the timeout despite the DA1 reply")
+	}
+}
+
+func TestQueryTimeout(t *testing.T) {
+	master, slave := openPty(t)
+	emulate(master, "")
+	_, _, err := QueryColors(slave, 50*time.Millisecond)
+	if !errors.Is(err, ErrQuery) {
+		t.Errorf("Have: %v, want: %v", err, ErrQuery)
+	}
+}
@@ -0,0 +1,177 @@
+package termcols
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseColorSpec(t *testing.T) {
+	cases := []struct {
+		spec   string
+		expOut Color
+		okExp  bool
+	}{
+		{"rgb:ffff/ffff/ffff", Color{255, 255, 255}, true},
+		{"rgb:0000/0000/0000", Color{0, 0, 0}, true},
+		{"rgb:2828/2c2c/3434", Color{40, 44, 52}, true},
+		{"rgb:fd/f6/e3", Color{253, 246, 227}, true},
+		{"rgb:f/8/0", Color{255, 136, 0}, true},
+		{"rgb:fff/000/800", Color{255, 0, 128}, true},
+		{"#fdf6e3", Color{253, 246, 227}, true},
+		{"#f80", Color{255, 136, 0}, true},
+		{"#ffff00000000", Color{255, 0, 0}, true},
+
+		{"", Color{}, false},
+		{"rgb:ffff/ffff", Color{}, false},
+		{"rgb:fffff/0/0", Color{}, false},
+		{"rgb:gg/00/00", Color{}, false},
+		{"rgb://", Color{}, false},
+		{"#ff00", Color{}, false},
+		{"#", Color{}, false},
+		{"rgba:ffff/ffff/ffff/ffff", Color{}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.spec, func(t *testing.T) {
+			out, ok := parseColorSpec(c.spec)
+			if ok != c.okExp {
+				t.Fatalf("Have: %t, want: %t", ok, c.okExp)
+			}
+			if out != c.expOut {
+				t.Errorf("Have: %v, want: %v", out, c.expOut)
+			}
+		})
+	}
+}
+
+func TestParseReplies(t *testing.T) {
+	cases := []struct {
+		name    string
+		buf     string
+		expOut  map[string]Color
+		expRest string
+		expDone bool
+	}{
+		{
+			"st-terminated",
+			"\033]11;rgb:ffff/ffff/ffff\033\\\033[?62;22c",
+			map[string]Color{"11": {255, 255, 255}},
+			"",
+			true,
+		},
+		{
+			"bel-terminated",
+			"\033]10;rgb:0000/0000/0000\a\033]11;rgb:ffff/0000/0000\a\033[?1;2c",
+			map[string]Color{"10": {0, 0, 0}, "11": {255, 0, 0}},
+			"",
+			true,
+		},
+		{
+			"palette",
+			"\033]4;1;rgb:cdcd/0000/0000\033\\",
+			map[string]Color{"4;1": {205, 0, 0}},
+			"",
+			false,
+		},
+		{
+			"incomplete-osc",
+			"\033]11;rgb:ffff/ff",
+			map[string]Color{},
+			"\033]11;rgb:ffff/ff",
+			false,
+		},
+		{
+			"incomplete-da1",
+			"\033]11;rgb:ffff/ffff/ffff\a\033[?62",
+			map[string]Color{"11": {255, 255, 255}},
+			"\033[?62",
+			false,
+		},
+		{
+			"da1-only",
+			"\033[?6c",
+			map[string]Color{},
+			"",
+			true,
+		},
+		{
+			"noise",
+			"abc\033[1;1R\033Xdef\033]11;?\a",
+			map[string]Color{},
+			"",
+			false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := make(map[string]Color)
+			rest, done := parseReplies([]byte(c.buf), out)
+			if done != c.expDone {
+				t.Errorf("Have: %t, want: %t", done, c.expDone)
+			}
+			if string(rest) != c.expRest {
+				t.Errorf("Have: %q, want: %q", rest, c.expRest)
+			}
+			if !reflect.DeepEqual(out, c.expOut) {
+				t.Errorf("Have: %v, want: %v", out, c.expOut)
+			}
+		})
+	}
+}
+
+func TestColorFgBgDark(t *testing.T) {
+	cases := []struct {
+		val     string
+		expDark bool
+		okExp   bool
+	}{
+		{"15;0", true, true},
+		{"0;15", false, true},
+		{"7;default;8", true, true},
+		{"0;7", false, true},
+		{"", false, false},
+		{"15;default", false, false},
+		{"15;16", false, false},
+	}
+	for _, c := range cases {
+		t.Run(c.val, func(t *testing.T) {
+			dark, ok := colorFgBgDark(c.val)
+			if ok != c.okExp || dark != c.expDark {
+				t.Errorf("Have: %t %t, want: %t %t", dark, ok, c.expDark, c.okExp)
+			}
+		})
+	}
+}
+
+func TestIsDark(t *testing.T) {
+	cases := []struct {
+		c   Color
+		exp bool
+	}{
+		{Color{0, 0, 0}, true},
+		{Color{0x28, 0x2c, 0x34}, true},
+		{Color{0x00, 0x2b, 0x36}, true},
+		{Color{0xfd, 0xf6, 0xe3}, false},
+		{Color{255, 255, 255}, false},
+		{Color{0x80, 0x80, 0x80}, false},
+	}
+	for _, c := range cases {
+		t.Run("", func(t *testing.T) {
+			if out := isDark(c.c); out != c.exp {
+				t.Errorf("Have: %t, want: %t", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestQueryNotTerminal(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, _, err := QueryColors(f, 10*time.Millisecond); err == nil {
+		t.Errorf("Have: nil, want: %v", ErrQuery)
+	}
+}
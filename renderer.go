@@ -0,0 +1,200 @@
+package termcols
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Color profiles telling how many colors a terminal can display. Profiles
+// are ordered, so a profile supports everything the lower ones support.
+const (
+	Plain Profile = iota
+	ANSI
+	ANSI256
+	TrueColor
+)
+
+// Profile is the set of SGR attributes supported by the output.
+type Profile int
+
+// Renderer colorizes text written to a particular writer. Attributes passed
+// to its methods are downgraded to its color profile, so that 24-bit colors
+// turn into the closest 8-bit or ANSI colors, and they are stripped
+// altogether for the Plain profile. A program can thus write colored output
+// to a terminal and plain output to a log file with the same code.
+//
+// A Renderer must not be reconfigured with SetProfile while it is in use by
+// other goroutines.
+type Renderer struct {
+	w       io.Writer
+	profile Profile
+}
+
+// NewRenderer returns a Renderer writing to w with the color profile detected
+// with DetectProfile.
+func NewRenderer(w io.Writer) *Renderer {
+	return &Renderer{w: w, profile: DetectProfile(w)}
+}
+
+// DetectProfile returns the color profile of the writer w. Writers that are
+// not terminals get the Plain profile unless the CLICOLOR_FORCE environment
+// variable is set to a value other than 0. The NO_COLOR environment variable
+// set to any value turns colors off regardless. Otherwise, the profile is
+// read from the COLORTERM and TERM environment variables and defaults to ANSI.
+func DetectProfile(w io.Writer) Profile {
+	var isTerm bool
+	if f, ok := w.(interface{ Fd() uintptr }); ok {
+		isTerm = term.IsTerminal(int(f.Fd()))
+	}
+	return detectProfile(isTerm, os.Getenv)
+}
+
+func (p Profile) String() string {
+	switch p {
+	case Plain:
+		return "plain"
+	case ANSI:
+		return "ansi"
+	case ANSI256:
+		return "ansi256"
+	case TrueColor:
+		return "truecolor"
+	}
+	return "Profile(" + strconv.Itoa(int(p)) + ")"
+}
+
+// Convert returns the attribute a downgraded to the profile p. With the ANSI
+// profile, 8-bit and 24-bit colors become the closest of the 16 ANSI colors,
+// and with the ANSI256 profile, 24-bit colors become the closest entries of
+// the color cube and the grayscale ramp. Other parameters are kept as they
+// are. The Plain profile turns any attribute into an empty one, while the
+// TrueColor profile leaves it unchanged.
+func (p Profile) Convert(a SgrAttr) SgrAttr {
+	switch {
+	case p <= Plain:
+		return ""
+	case p >= TrueColor:
+		return a
+	}
+	params, ok := a.Params()
+	if !ok {
+		return a
+	}
+	out := make([]string, 0, len(params))
+	var changed bool
+	for i := 0; i < len(params); i++ {
+		l, c, n, ok := paramColor(params[i:])
+		switch {
+		case ok && n > 1 && p == ANSI:
+			out = append(out, ansiParam(l, c.nearest(0, 16)))
+		case ok && n == 5 && p == ANSI256:
+			out = append(out, string(l[len(Csi):]), "5", strconv.Itoa(int(c.PaletteIndex())))
+		default:
+			if !ok {
+				n = 1
+			}
+			for _, q := range params[i : i+n] {
+				out = append(out, strconv.Itoa(q))
+			}
+			i += n - 1
+			continue
+		}
+		i += n - 1
+		changed = true
+	}
+	if !changed {
+		return a
+	}
+	return SgrAttr(Csi + strings.Join(out, ";") + "m")
+}
+
+// Profile returns the color profile of the renderer.
+func (r *Renderer) Profile() Profile {
+	return r.profile
+}
+
+// SetProfile overrides the detected color profile of the renderer, for
+// instance when the user asks for colors explicitly.
+func (r *Renderer) SetProfile(p Profile) {
+	r.profile = p
+}
+
+// Writer returns the writer the renderer writes to.
+func (r *Renderer) Writer() io.Writer {
+	return r.w
+}
+
+// Colorize works like [Colorize] with attrs downgraded to the profile of the
+// renderer. The string s is returned as it is for the Plain profile.
+func (r *Renderer) Colorize(s string, attrs ...SgrAttr) string {
+	return Colorize(s, r.convert(attrs)...)
+}
+
+// Style returns the Style applying attrs downgraded to the profile of the
+// renderer. For the Plain profile, the style has no attributes and renders
+// text unchanged.
+func (r *Renderer) Style(attrs ...SgrAttr) Style {
+	return NewStyle(r.convert(attrs)...)
+}
+
+// WriteColorized writes the string s colorized with attrs downgraded to the
+// profile of the renderer to its writer the same way as [WriteColorized].
+func (r *Renderer) WriteColorized(s string, attrs ...SgrAttr) (int, error) {
+	return WriteColorized(r.w, s, r.convert(attrs)...)
+}
+
+// Convert downgrades attrs to the profile of the renderer and drops the ones
+// that end up empty.
+func (r *Renderer) convert(attrs []SgrAttr) []SgrAttr {
+	if r.profile >= TrueColor {
+		return attrs
+	}
+	result := make([]SgrAttr, 0, len(attrs))
+	for _, a := range attrs {
+		if a = r.profile.Convert(a); a != "" {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// DetectProfile picks the color profile given whether the output isTerm
+// terminal and the environment read with getenv.
+func detectProfile(isTerm bool, getenv func(string) string) Profile {
+	if getenv("NO_COLOR") != "" {
+		return Plain
+	}
+	force := getenv("CLICOLOR_FORCE")
+	forced := force != "" && force != "0"
+	t := getenv("TERM")
+	if !forced && (!isTerm || t == "dumb") {
+		return Plain
+	}
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	switch {
+	case strings.HasSuffix(t, "-direct"):
+		return TrueColor
+	case strings.Contains(t, "256color"):
+		return ANSI256
+	}
+	return ANSI
+}
+
+// AnsiParam returns the SGR parameter of the ANSI color i for the layer l.
+func ansiParam(l Layer, i uint8) string {
+	base := 30
+	if l == BG {
+		base = 40
+	}
+	if i >= 8 {
+		base += 60 - 8
+	}
+	return strconv.Itoa(base + int(i))
+}
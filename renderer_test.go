@@ -0,0 +1,135 @@
+package termcols
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDetectProfile(t *testing.T) {
+	cases := []struct {
+		name   string
+		isTerm bool
+		env    map[string]string
+		exp    Profile
+	}{
+		{"not-term", false, map[string]string{"TERM": "xterm-256color"}, Plain},
+		{"default", true, map[string]string{}, ANSI},
+		{"xterm", true, map[string]string{"TERM": "xterm"}, ANSI},
+		{"256color", true, map[string]string{"TERM": "xterm-256color"}, ANSI256},
+		{"direct", true, map[string]string{"TERM": "xterm-direct"}, TrueColor},
+		{"colorterm", true, map[string]string{"TERM": "xterm", "COLORTERM": "truecolor"}, TrueColor},
+		{"24bit", true, map[string]string{"COLORTERM": "24BIT"}, TrueColor},
+		{"dumb", true, map[string]string{"TERM": "dumb"}, Plain},
+		{"no-color", true, map[string]string{"NO_COLOR": "1", "COLORTERM": "truecolor"}, Plain},
+		{"force", false, map[string]string{"CLICOLOR_FORCE": "1"}, ANSI},
+		{"force-256", false, map[string]string{"CLICOLOR_FORCE": "1", "TERM": "screen-256color"}, ANSI256},
+		{"force-zero", false, map[string]string{"CLICOLOR_FORCE": "0"}, Plain},
+		{"force-no-color", false, map[string]string{"CLICOLOR_FORCE": "1", "NO_COLOR": "1"}, Plain},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			getenv := func(k string) string { return c.env[k] }
+			if out := detectProfile(c.isTerm, getenv); out != c.exp {
+				t.Errorf("Have: %v, want: %v", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestDetectProfileWriter(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+	if out := DetectProfile(&bytes.Buffer{}); out != Plain {
+		t.Errorf("Have: %v, want: %v", out, Plain)
+	}
+}
+
+func TestProfileConvert(t *testing.T) {
+	cases := []struct {
+		name string
+		p    Profile
+		a    SgrAttr
+		exp  SgrAttr
+	}{
+		{"plain", Plain, Rgb24(FG, 255, 0, 0), ""},
+		{"plain-style", Plain, Bold, ""},
+		{"truecolor", TrueColor, Rgb24(FG, 1, 2, 3), Rgb24(FG, 1, 2, 3)},
+		{"ansi-rgb24", ANSI, Rgb24(FG, 255, 0, 0), "\033[91m"},
+		{"ansi-rgb24-bg", ANSI, Rgb24(BG, 0, 0, 0), "\033[40m"},
+		{"ansi-rgb8", ANSI, Rgb8(BG, 4), "\033[44m"},
+		{"ansi-rgb8-bright", ANSI, Rgb8(FG, 15), "\033[97m"},
+		{"ansi-named", ANSI, RedFg, RedFg},
+		{"ansi-style", ANSI, Bold, Bold},
+		{"ansi-combined", ANSI, "\033[1;38;2;0;0;0;48;5;15m", "\033[1;30;107m"},
+		{"ansi-cursor", ANSI, "\033[2K", "\033[2K"},
+		{"ansi256-rgb24", ANSI256, Rgb24(FG, 255, 135, 0), Rgb8(FG, 208)},
+		{"ansi256-rgb8", ANSI256, Rgb8(BG, 57), Rgb8(BG, 57)},
+		{"ansi256-combined", ANSI256, "\033[4;48;2;0;0;0;39m", "\033[4;48;5;16;39m"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if out := c.p.Convert(c.a); out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestProfileString(t *testing.T) {
+	cases := []struct {
+		p   Profile
+		exp string
+	}{
+		{Plain, "plain"},
+		{ANSI, "ansi"},
+		{ANSI256, "ansi256"},
+		{TrueColor, "truecolor"},
+		{Profile(9), "Profile(9)"},
+	}
+	for _, c := range cases {
+		t.Run(c.exp, func(t *testing.T) {
+			if out := c.p.String(); out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestRenderer(t *testing.T) {
+	attrs := []SgrAttr{Bold, Rgb24(FG, 255, 135, 0)}
+	cases := []struct {
+		p   Profile
+		exp string
+	}{
+		{Plain, "text"},
+		{ANSI256, string(Bold) + string(Rgb8(FG, 208)) + "text" + string(Reset)},
+		{TrueColor, string(Bold) + string(Rgb24(FG, 255, 135, 0)) + "text" + string(Reset)},
+	}
+	for _, c := range cases {
+		t.Run(c.p.String(), func(t *testing.T) {
+			var b bytes.Buffer
+			r := NewRenderer(&b)
+			r.SetProfile(c.p)
+			if r.Profile() != c.p || r.Writer() != &b {
+				t.Fatalf("Have: %v %v, want: %v %v", r.Profile(), r.Writer(), c.p, &b)
+			}
+			if out := r.Colorize("text", attrs...); out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+			if out := r.Style(attrs...).Render("text"); out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+			n, err := r.WriteColorized("text", attrs...)
+			if err != nil || n != len(c.exp) || b.String() != c.exp {
+				t.Errorf("Have: %q %d %v, want: %q %d nil", b.String(), n, err, c.exp, len(c.exp))
+			}
+		})
+	}
+}
+
+func TestRendererWriteError(t *testing.T) {
+	r := NewRenderer(errWriter{})
+	if _, err := r.WriteColorized("text", Bold); err != errWrite {
+		t.Errorf("Have: %v, want: %v", err, errWrite)
+	}
+}
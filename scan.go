@@ -0,0 +1,85 @@
+package termcols
+
+import (
+	"bytes"
+)
+
+// MaxSeqLen caps the length of a CSI control sequence recognized by
+// ScanSequences. Longer ones are treated as plain text.
+const maxSeqLen = 256
+
+// NOTE: OSC control sequences carry titles and hyperlink URLs, so they are
+// allowed to be much longer than CSI control sequences.
+const maxOscLen = 4096
+
+// ScanSequences is a split function for a [bufio.Scanner] that splits text into
+// tokens that are either a single CSI or OSC control sequence, such as an SGR
+// attribute or a hyperlink, or a run of text that contains no control
+// sequence. CSI sequences end with their final byte and OSC sequences with BEL
+// or ST. A run of text is returned as soon as it is followed by an escape
+// character, or right away when it makes up the rest of the data, so text is
+// not held back waiting for more input. An escape character that does not
+// start a well-formed CSI or OSC control sequence is returned as a token of
+// its own.
+func ScanSequences(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if len(data) == 0 {
+		return 0, nil, nil
+	}
+	if data[0] != Esc[0] {
+		if i := bytes.IndexByte(data, Esc[0]); i >= 0 {
+			return i, data[:i], nil
+		}
+		return len(data), data, nil
+	}
+	n := seqLen(data, atEOF)
+	if n == 0 {
+		return 0, nil, nil
+	}
+	return n, data[:n], nil
+}
+
+// SeqLen returns the length of the control sequence at the start of s, which
+// starts with an escape character. It returns 1 when the escape character does
+// not start a well-formed CSI or OSC control sequence, and 0 when more data
+// is needed to tell, unless atEOF is set. It is shared by ScanSequences and
+// the functions measuring the width of text, so that both agree on what a
+// control sequence is.
+func seqLen[T string | []byte](s T, atEOF bool) int {
+	if len(s) < 2 {
+		if atEOF {
+			return 1
+		}
+		return 0
+	}
+	limit := maxSeqLen
+	switch s[1] {
+	case Csi[1]:
+		for i := 2; i < len(s) && i < limit; i++ {
+			switch c := s[i]; {
+			case c >= 0x40 && c <= 0x7e:
+				return i + 1
+			case c < 0x20 || c > 0x3f:
+				return 1
+			}
+		}
+	case Osc[1]:
+		limit = maxOscLen
+		for i := 2; i < len(s) && i < limit; i++ {
+			switch {
+			case s[i] == '\a':
+				return i + 1
+			case s[i] != Esc[0], i+1 == len(s):
+			case s[i+1] == St[1]:
+				return i + len(St)
+			default:
+				return 1
+			}
+		}
+	default:
+		return 1
+	}
+	if atEOF || len(s) >= limit {
+		return 1
+	}
+	return 0
+}
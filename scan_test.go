@@ -0,0 +1,97 @@
+package termcols
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestScanSequences(t *testing.T) {
+	cases := []struct {
+		name string
+		s    string
+		exp  []string
+	}{
+		{"empty", "", nil},
+		{"text", "hello", []string{"hello"}},
+		{
+			"sgr",
+			"\033[1;31mhello\033[0m",
+			[]string{"\033[1;31m", "hello", "\033[0m"},
+		},
+		{
+			"adjacent",
+			"\033[1m\033[38;2;1;2;3mx",
+			[]string{"\033[1m", "\033[38;2;1;2;3m", "x"},
+		},
+		{
+			"other-csi",
+			"a\033[2Jb\033[?25l",
+			[]string{"a", "\033[2J", "b", "\033[?25l"},
+		},
+		{"lone-esc", "a\033b", []string{"a", "\033", "b"}},
+		{
+			"osc-bel",
+			"\033]0;title\ax",
+			[]string{"\033]0;title\a", "x"},
+		},
+		{
+			"osc-st",
+			"\033]8;;https://example.com\033\\link\033]8;;\033\\",
+			[]string{"\033]8;;https://example.com\033\\", "link", "\033]8;;\033\\"},
+		},
+		{"osc-unterminated", "\033]0;t", []string{"\033", "]0;t"}},
+		{"osc-bad-esc", "\033]0;t\033[1mx", []string{"\033", "]0;t", "\033[1m", "x"}},
+		{
+			"osc-too-long",
+			"\033]" + strings.Repeat("1", maxOscLen) + "\a",
+			[]string{"\033", "]" + strings.Repeat("1", maxOscLen) + "\a"},
+		},
+		{"trailing-esc", "a\033", []string{"a", "\033"}},
+		{"unterminated", "a\033[1;2", []string{"a", "\033", "[1;2"}},
+		{"invalid-byte", "\033[1\nm", []string{"\033", "[1\nm"}},
+		{
+			"too-long",
+			"\033[" + strings.Repeat("1", maxSeqLen) + "m",
+			[]string{"\033", "[" + strings.Repeat("1", maxSeqLen) + "m"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for _, name := range []string{"whole", "one-byte"} {
+				r := iotest.OneByteReader(strings.NewReader(c.s))
+				if name == "whole" {
+					r = strings.NewReader(c.s)
+				}
+				sc := bufio.NewScanner(r)
+				sc.Split(ScanSequences)
+				var out []string
+				for sc.Scan() {
+					out = append(out, sc.Text())
+				}
+				// NOTE: Text comes in pieces as the scanner fills its buffer.
+				out = mergeText(out)
+				if !reflect.DeepEqual(out, c.exp) {
+					t.Errorf("%s: Have: %q, want: %q", name, out, c.exp)
+				}
+			}
+		})
+	}
+}
+
+// MergeText joins adjacent tokens of plain text, which come in pieces when
+// the input arrives byte by byte or fills the scanner buffer.
+func mergeText(tokens []string) []string {
+	var out []string
+	for _, tok := range tokens {
+		n := len(out)
+		if n > 0 && !strings.HasPrefix(tok, Esc) && !strings.HasPrefix(out[n-1], Esc) {
+			out[n-1] += tok
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
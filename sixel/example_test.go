@@ -0,0 +1,34 @@
+package sixel_test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"strings"
+
+	"github.com/mdm-code/termcols/sixel"
+)
+
+// ExampleEncoder shows how to encode a two-color image as Sixel graphics.
+func ExampleEncoder() {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 6))
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 4; x++ {
+			c := color.NRGBA{255, 0, 0, 255}
+			if x >= 2 {
+				c = color.NRGBA{0, 0, 255, 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var s strings.Builder
+	enc := sixel.Encoder{Colors: 16}
+	if err := enc.Encode(&s, img); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	fmt.Printf("%q\n", s.String())
+	// Output:
+	// "\x1bP0;1;0q\"1;1;4;6#0;2;0;0;100#1;2;100;0;0#0??~~$#1~~\x1b\\"
+}
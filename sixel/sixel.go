@@ -0,0 +1,384 @@
+/*
+Package sixel encodes images as Sixel graphics, which terminals such as
+xterm, mlterm, foot, WezTerm and the Windows Terminal display as real pixels
+rather than characters.
+
+Sixel images use a palette of indexed colors, so an image is quantized to at
+most the configured number of colors with the median cut algorithm first,
+and the quantization error is spread over neighbouring pixels with
+Floyd-Steinberg dithering. The output is a DCS control sequence that can be
+written straight to the terminal. Encoding is deterministic, so the same
+image always yields the same bytes.
+
+# Usage
+
+	package main
+
+	import (
+		"image/png"
+		"os"
+
+		"github.com/mdm-code/termcols/sixel"
+	)
+
+	func main() {
+		f, _ := os.Open("logo.png")
+		defer f.Close()
+		img, _ := png.Decode(f)
+		enc := sixel.Encoder{Colors: 64}
+		enc.Encode(os.Stdout, img)
+	}
+*/
+package sixel
+
+import (
+	"bufio"
+	"errors"
+	"image"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/mdm-code/termcols"
+)
+
+// MaxColors is the largest palette supported by common terminals.
+const MaxColors = 256
+
+// Sixel control sequences
+const (
+	// Start starts a Sixel image whose unpainted pixels are left
+	// transparent.
+	Start = termcols.Esc + "P0;1;0q"
+	End   = termcols.St
+)
+
+// ErrEmpty indicates that the image has no pixels to encode.
+var ErrEmpty = errors.New("Empty image")
+
+// Encoder encodes images as Sixel graphics. The zero value encodes images
+// with MaxColors colors and dithering.
+type Encoder struct {
+	// Colors is the size of the palette in the range [2, MaxColors]. Values
+	// out of the range are clamped, and 0 stands for MaxColors.
+	Colors int
+
+	// NoDither turns dithering off, so that each pixel gets the closest
+	// palette color, which suits flat graphics such as logos.
+	NoDither bool
+}
+
+// Encode writes the image m to w as Sixel graphics with the default Encoder.
+func Encode(w io.Writer, m image.Image) error {
+	var e Encoder
+	return e.Encode(w, m)
+}
+
+// Encode writes the image m to w as Sixel graphics. Pixels that are mostly
+// transparent are not painted.
+func (e *Encoder) Encode(w io.Writer, m image.Image) error {
+	b := m.Bounds()
+	if b.Empty() {
+		return ErrEmpty
+	}
+	n := e.Colors
+	if n <= 0 || n > MaxColors {
+		n = MaxColors
+	}
+	n = max(n, 2)
+	pixels, opaque := readPixels(m)
+	palette := quantize(pixels, opaque, n)
+	indices := mapPixels(pixels, opaque, b.Dx(), palette, !e.NoDither)
+
+	bw := bufio.NewWriter(w)
+	writeSixels(bw, b.Dx(), b.Dy(), palette, indices)
+	return bw.Flush()
+}
+
+// ReadPixels returns the colors of the pixels of the image m in row-major
+// order along with whether each one is opaque enough to be painted.
+func readPixels(m image.Image) ([]termcols.Color, []bool) {
+	b := m.Bounds()
+	pixels := make([]termcols.Color, 0, b.Dx()*b.Dy())
+	opaque := make([]bool, 0, b.Dx()*b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, a := m.At(x, y).RGBA()
+			if a < 0x8000 {
+				pixels, opaque = append(pixels, termcols.Color{}), append(opaque, false)
+				continue
+			}
+			// NOTE: Colors returned by RGBA are alpha-premultiplied.
+			pixels = append(pixels, termcols.Color{
+				R: uint8(r * 0xff / a),
+				G: uint8(g * 0xff / a),
+				B: uint8(bl * 0xff / a),
+			})
+			opaque = append(opaque, true)
+		}
+	}
+	return pixels, opaque
+}
+
+// WriteSixels writes the Sixel stream of a width by height image with pixels
+// given as palette indices, where -1 marks unpainted pixels.
+func writeSixels(w *bufio.Writer, width, height int, palette []termcols.Color, indices []int) {
+	w.WriteString(Start)
+	w.WriteString(`"1;1;` + strconv.Itoa(width) + ";" + strconv.Itoa(height))
+	for i, c := range palette {
+		w.WriteString("#" + strconv.Itoa(i) + ";2;" + percent(c.R) + ";" + percent(c.G) + ";" + percent(c.B))
+	}
+	row := make([]byte, width)
+	for top := 0; top < height; top += 6 {
+		if top > 0 {
+			w.WriteByte('-')
+		}
+		// NOTE: Each band of six rows is painted one color at a time, and the
+		// carriage return $ brings the cursor back to the start of the band
+		// before the next color.
+		first := true
+		for ci := range palette {
+			used := false
+			for x := 0; x < width; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && top+dy < height; dy++ {
+					if indices[(top+dy)*width+x] == ci {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = '?' + bits
+				used = used || bits != 0
+			}
+			if !used {
+				continue
+			}
+			if !first {
+				w.WriteByte('$')
+			}
+			first = false
+			w.WriteString("#" + strconv.Itoa(ci))
+			writeRun(w, trimEmpty(row))
+		}
+	}
+	w.WriteString(End)
+}
+
+// WriteRun writes the sixel characters of row compressed with the repeat
+// introducer ! for runs longer than three characters.
+func writeRun(w *bufio.Writer, row []byte) {
+	for i := 0; i < len(row); {
+		j := i + 1
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			w.WriteString("!" + strconv.Itoa(n))
+			w.WriteByte(row[i])
+		} else {
+			w.Write(row[i:j])
+		}
+		i = j
+	}
+}
+
+// TrimEmpty drops the trailing sixels of row that paint no pixels.
+func trimEmpty(row []byte) []byte {
+	n := len(row)
+	for n > 0 && row[n-1] == '?' {
+		n--
+	}
+	return row[:n]
+}
+
+// Percent converts the channel value v into the percentage used by Sixel
+// color definitions.
+func percent(v uint8) string {
+	return strconv.Itoa((int(v)*100 + 127) / 255)
+}
+
+// MapPixels returns palette indices of pixels of an image width pixels wide.
+// With dither set, the difference between a pixel and its palette color is
+// spread over the neighbouring pixels with the Floyd-Steinberg weights.
+func mapPixels(pixels []termcols.Color, opaque []bool, width int, palette []termcols.Color, dither bool) []int {
+	indices := make([]int, len(pixels))
+	var errs [][3]float64
+	if dither {
+		errs = make([][3]float64, len(pixels))
+	}
+	for i, c := range pixels {
+		if !opaque[i] {
+			indices[i] = -1
+			continue
+		}
+		want := [3]float64{float64(c.R), float64(c.G), float64(c.B)}
+		if dither {
+			for k := range want {
+				want[k] = min(max(want[k]+errs[i][k], 0), 255)
+			}
+		}
+		idx := nearest(palette, want)
+		indices[i] = idx
+		if !dither {
+			continue
+		}
+		p := palette[idx]
+		diff := [3]float64{want[0] - float64(p.R), want[1] - float64(p.G), want[2] - float64(p.B)}
+		x := i % width
+		spread := func(j int, weight float64) {
+			if j < len(pixels) && opaque[j] {
+				for k := range diff {
+					errs[j][k] += diff[k] * weight
+				}
+			}
+		}
+		if x+1 < width {
+			spread(i+1, 7.0/16)
+			spread(i+width+1, 1.0/16)
+		}
+		if x > 0 {
+			spread(i+width-1, 3.0/16)
+		}
+		spread(i+width, 5.0/16)
+	}
+	return indices
+}
+
+// Nearest returns the index of the palette color closest to the color c in
+// RGB space, the first one on ties.
+func nearest(palette []termcols.Color, c [3]float64) int {
+	best, bestDist := 0, -1.0
+	for i, p := range palette {
+		dr, dg, db := c[0]-float64(p.R), c[1]-float64(p.G), c[2]-float64(p.B)
+		if d := dr*dr + dg*dg + db*db; bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// ColorCount is a distinct color of an image along with the number of pixels
+// of that color.
+type colorCount struct {
+	c termcols.Color
+	n int
+}
+
+// Quantize returns a palette of at most n colors representing the opaque
+// pixels with the median cut algorithm. Images with no more than n distinct
+// colors keep all of them.
+func quantize(pixels []termcols.Color, opaque []bool, n int) []termcols.Color {
+	counts := make(map[termcols.Color]int)
+	for i, c := range pixels {
+		if opaque[i] {
+			counts[c]++
+		}
+	}
+	colors := make([]colorCount, 0, len(counts))
+	for c, k := range counts {
+		colors = append(colors, colorCount{c, k})
+	}
+	// NOTE: Map iteration order is random, so colors are sorted to keep the
+	// output deterministic.
+	sort.Slice(colors, func(i, j int) bool {
+		return key(colors[i].c) < key(colors[j].c)
+	})
+	if len(colors) <= n {
+		palette := make([]termcols.Color, len(colors))
+		for i, cc := range colors {
+			palette[i] = cc.c
+		}
+		if len(palette) == 0 {
+			palette = append(palette, termcols.Color{})
+		}
+		return palette
+	}
+	boxes := [][]colorCount{colors}
+	for len(boxes) < n {
+		bi, ch, span := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			c, s := widestChannel(box)
+			if s > span {
+				bi, ch, span = i, c, s
+			}
+		}
+		if bi < 0 {
+			break
+		}
+		lo, hi := split(boxes[bi], ch)
+		boxes[bi] = lo
+		boxes = append(boxes, hi)
+	}
+	palette := make([]termcols.Color, len(boxes))
+	for i, box := range boxes {
+		palette[i] = average(box)
+	}
+	return palette
+}
+
+// WidestChannel returns the RGB channel with the largest range of values in
+// the box along with the range.
+func widestChannel(box []colorCount) (int, int) {
+	lo, hi := [3]int{255, 255, 255}, [3]int{}
+	for _, cc := range box {
+		for k, v := range channels(cc.c) {
+			lo[k], hi[k] = min(lo[k], v), max(hi[k], v)
+		}
+	}
+	best := 0
+	for k := 1; k < 3; k++ {
+		if hi[k]-lo[k] > hi[best]-lo[best] {
+			best = k
+		}
+	}
+	return best, hi[best] - lo[best]
+}
+
+// Split sorts the box along the channel ch and splits it at the median pixel.
+func split(box []colorCount, ch int) ([]colorCount, []colorCount) {
+	sort.SliceStable(box, func(i, j int) bool {
+		return channels(box[i].c)[ch] < channels(box[j].c)[ch]
+	})
+	total := 0
+	for _, cc := range box {
+		total += cc.n
+	}
+	at, seen := 1, 0
+	for i, cc := range box[:len(box)-1] {
+		seen += cc.n
+		at = i + 1
+		if seen*2 >= total {
+			break
+		}
+	}
+	return box[:at:at], box[at:]
+}
+
+// Average returns the average color of the box weighted by pixel counts.
+func average(box []colorCount) termcols.Color {
+	var sum [3]int
+	total := 0
+	for _, cc := range box {
+		for k, v := range channels(cc.c) {
+			sum[k] += v * cc.n
+		}
+		total += cc.n
+	}
+	return termcols.Color{
+		R: uint8((sum[0] + total/2) / total),
+		G: uint8((sum[1] + total/2) / total),
+		B: uint8((sum[2] + total/2) / total),
+	}
+}
+
+// Channels returns the channels of the color c as integers.
+func channels(c termcols.Color) [3]int {
+	return [3]int{int(c.R), int(c.G), int(c.B)}
+}
+
+// Key packs the color c into an integer used to sort colors.
+func key(c termcols.Color) uint32 {
+	return uint32(c.R)<<16 | uint32(c.G)<<8 | uint32(c.B)
+}
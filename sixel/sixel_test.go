@@ -0,0 +1,220 @@
+package sixel
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mdm-code/termcols"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// TestImage returns a width by height image colored with f.
+func testImage(width, height int, f func(x, y int) color.Color) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, f(x, y))
+		}
+	}
+	return img
+}
+
+// Test that encoded images match the golden files in testdata.
+func TestEncodeGolden(t *testing.T) {
+	gradient := testImage(32, 14, func(x, y int) color.Color {
+		return color.NRGBA{uint8(x * 8), uint8(y * 18), 128, 255}
+	})
+	cases := []struct {
+		name string
+		enc  Encoder
+		img  image.Image
+	}{
+		{
+			"checker",
+			Encoder{},
+			testImage(8, 8, func(x, y int) color.Color {
+				if (x+y)%2 == 0 {
+					return color.NRGBA{255, 255, 255, 255}
+				}
+				return color.NRGBA{0, 0, 0, 255}
+			}),
+		},
+		{
+			"transparent",
+			Encoder{},
+			testImage(10, 7, func(x, y int) color.Color {
+				if x < 5 {
+					return color.NRGBA{255, 0, 0, 255}
+				}
+				return color.NRGBA{0, 0, 255, 64}
+			}),
+		},
+		{"gradient", Encoder{}, gradient},
+		{"gradient_8", Encoder{Colors: 8}, gradient},
+		{"gradient_8_nodither", Encoder{Colors: 8, NoDither: true}, gradient},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := c.enc.Encode(&b, c.img); err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("testdata", c.name+".sixel")
+			if *update {
+				if err := os.WriteFile(golden, b.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have := b.Bytes(); !bytes.Equal(have, want) {
+				t.Errorf("Have: %q; want: %q", have, want)
+			}
+		})
+	}
+}
+
+// Test that a small image is encoded into the expected Sixel stream.
+func TestEncode(t *testing.T) {
+	img := testImage(5, 2, func(x, y int) color.Color {
+		if y == 0 {
+			return color.NRGBA{255, 0, 0, 255}
+		}
+		return color.NRGBA{0, 0, 0, 0}
+	})
+	var b bytes.Buffer
+	if err := Encode(&b, img); err != nil {
+		t.Fatal(err)
+	}
+	want := Start + `"1;1;5;2#0;2;100;0;0#0!5@` + End
+	if have := b.String(); have != want {
+		t.Errorf("Have: %q; want: %q", have, want)
+	}
+}
+
+// Test that encoding an empty image returns an error.
+func TestEncodeEmpty(t *testing.T) {
+	var b bytes.Buffer
+	img := image.NewNRGBA(image.Rect(0, 0, 0, 4))
+	if err := Encode(&b, img); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Have: %v; want: %v", err, ErrEmpty)
+	}
+	if b.Len() != 0 {
+		t.Errorf("Have: %q; want: %q", b.String(), "")
+	}
+}
+
+// Test that the size of the palette is limited to the configured colors.
+func TestQuantize(t *testing.T) {
+	img := testImage(16, 16, func(x, y int) color.Color {
+		return color.NRGBA{uint8(x * 16), uint8(y * 16), 0, 255}
+	})
+	pixels, opaque := readPixels(img)
+	cases := []struct {
+		n   int
+		exp int
+	}{
+		{2, 2},
+		{16, 16},
+		{255, 255},
+		{256, 256},
+		{1000, 256},
+	}
+	for _, c := range cases {
+		if have := len(quantize(pixels, opaque, c.n)); have != c.exp {
+			t.Errorf("Have: %d; want: %d", have, c.exp)
+		}
+	}
+}
+
+// Test that images with few colors keep their exact colors.
+func TestQuantizeExact(t *testing.T) {
+	pixels := []termcols.Color{{R: 9}, {G: 9}, {R: 9}, {B: 9}}
+	opaque := []bool{true, true, true, false}
+	want := []termcols.Color{{G: 9}, {R: 9}}
+	have := quantize(pixels, opaque, 4)
+	if len(have) != len(want) {
+		t.Fatalf("Have: %v; want: %v", have, want)
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			t.Errorf("Have: %v; want: %v", have, want)
+		}
+	}
+}
+
+// Test that dithering mixes palette colors to approximate the mean color.
+func TestMapPixelsDither(t *testing.T) {
+	palette := []termcols.Color{{}, {R: 255, G: 255, B: 255}}
+	pixels := make([]termcols.Color, 64)
+	opaque := make([]bool, 64)
+	for i := range pixels {
+		pixels[i], opaque[i] = termcols.Color{R: 128, G: 128, B: 128}, true
+	}
+	cases := []struct {
+		dither bool
+		exp    int
+	}{
+		{false, 64},
+		{true, 32},
+	}
+	for _, c := range cases {
+		white := 0
+		for _, idx := range mapPixels(pixels, opaque, 8, palette, c.dither) {
+			white += idx
+		}
+		if white != c.exp {
+			t.Errorf("Have: %d; want: %d", white, c.exp)
+		}
+	}
+}
+
+// Test that runs of repeated sixels are compressed.
+func TestWriteRun(t *testing.T) {
+	cases := []struct {
+		row string
+		exp string
+	}{
+		{"", ""},
+		{"~~~", "~~~"},
+		{"~~~~", "!4~"},
+		{"@@@@@A~~", "!5@A~~"},
+	}
+	for _, c := range cases {
+		var b bytes.Buffer
+		w := bufio.NewWriter(&b)
+		writeRun(w, []byte(c.row))
+		w.Flush()
+		if have := b.String(); have != c.exp {
+			t.Errorf("Have: %q; want: %q", have, c.exp)
+		}
+	}
+}
+
+// Test that channel values are converted into percentages.
+func TestPercent(t *testing.T) {
+	cases := []struct {
+		v   uint8
+		exp string
+	}{
+		{0, "0"},
+		{1, "0"},
+		{128, "50"},
+		{255, "100"},
+	}
+	for _, c := range cases {
+		if have := percent(c.v); have != c.exp {
+			t.Errorf("Have: %s; want: %s", have, c.exp)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package termcols
+
+import (
+	"strconv"
+	"strings"
+)
+
+// State describes the display attributes in effect on the terminal at a given
+// point of the output. The zero value corresponds to the terminal defaults,
+// that is the state right after the [Reset] control sequence.
+//
+// Colors are kept as SGR control sequences, so Fg and Bg can hold any of the
+// named foreground/background colors as well as [Rgb8] and [Rgb24] values. An
+// empty Fg or Bg stands for the default terminal color.
+type State struct {
+	Bold      bool
+	Faint     bool
+	Italic    bool
+	Underline bool
+	Blink     bool
+	Reverse   bool
+	Hide      bool
+	Strike    bool
+	Fg        SgrAttr
+	Bg        SgrAttr
+}
+
+// NewState returns the State resulting from applying attrs in sequence to the
+// terminal defaults.
+func NewState(attrs ...SgrAttr) State {
+	return State{}.Apply(attrs...)
+}
+
+// Apply returns a copy of the state s with attrs SGR control sequences applied
+// in sequence, the same way a terminal would interpret them. Parameters the
+// State does not keep track of, and attrs that are not SGR control sequences,
+// are ignored.
+func (s State) Apply(attrs ...SgrAttr) State {
+	for _, a := range attrs {
+		params, ok := sgrParams(a)
+		if !ok {
+			continue
+		}
+		s = s.apply(splitParams(params))
+	}
+	return s
+}
+
+// Transition returns the shortest SGR control sequence that changes the
+// terminal display attributes from the from state to the to state. All the
+// required parameters are merged into a single control sequence. It either
+// switches off and on the attributes that differ between the two states or
+// resets the terminal and sets the to state anew, depending on which one of
+// the two takes fewer bytes. An empty SgrAttr is returned when both states are
+// the same.
+func Transition(from, to State) SgrAttr {
+	if from == to {
+		return ""
+	}
+	incr := strings.Join(diffParams(from, to), ";")
+	reset := strings.Join(append([]string{"0"}, diffParams(State{}, to)...), ";")
+	if len(reset) < len(incr) {
+		return SgrAttr(Csi + reset + "m")
+	}
+	return SgrAttr(Csi + incr + "m")
+}
+
+// Reapply returns the string s with the attributes of the base state turned
+// on again after each control sequence in s that switches any of them off,
+// such as the reset closing a colorized word. Text written in the base state
+// can thus embed colorized strings and keep its own style after them.
+// Attributes that base leaves at the terminal defaults are not touched. The
+// string s is returned as it is when base is the zero State or s has no
+// control sequences.
+func Reapply(s string, base State) string {
+	if base == (State{}) || !strings.Contains(s, Esc) {
+		return s
+	}
+	var b strings.Builder
+	state := base
+	data := []byte(s)
+	for len(data) > 0 {
+		n, tok, _ := ScanSequences(data, true)
+		b.Write(tok)
+		data = data[n:]
+		if tok[0] != Esc[0] {
+			continue
+		}
+		state = state.Apply(SgrAttr(tok))
+		want := state.merge(base)
+		b.WriteString(string(Transition(state, want)))
+		state = want
+	}
+	return b.String()
+}
+
+// Merge returns the state s with the attributes it lacks taken from the base
+// state.
+func (s State) merge(base State) State {
+	s.Bold = s.Bold || base.Bold
+	s.Faint = s.Faint || base.Faint
+	s.Italic = s.Italic || base.Italic
+	s.Underline = s.Underline || base.Underline
+	s.Blink = s.Blink || base.Blink
+	s.Reverse = s.Reverse || base.Reverse
+	s.Hide = s.Hide || base.Hide
+	s.Strike = s.Strike || base.Strike
+	if s.Fg == "" {
+		s.Fg = base.Fg
+	}
+	if s.Bg == "" {
+		s.Bg = base.Bg
+	}
+	return s
+}
+
+// Apply interprets a single list of SGR parameters.
+func (s State) apply(params []int) State {
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		switch {
+		case p == 0:
+			s = State{}
+		case p == 1:
+			s.Bold = true
+		case p == 2:
+			s.Faint = true
+		case p == 3:
+			s.Italic = true
+		case p == 4:
+			s.Underline = true
+		case p == 5:
+			s.Blink = true
+		case p == 7:
+			s.Reverse = true
+		case p == 8:
+			s.Hide = true
+		case p == 9:
+			s.Strike = true
+		case p == 22:
+			s.Bold, s.Faint = false, false
+		case p == 23:
+			s.Italic = false
+		case p == 24:
+			s.Underline = false
+		case p == 25:
+			s.Blink = false
+		case p == 27:
+			s.Reverse = false
+		case p == 28:
+			s.Hide = false
+		case p == 29:
+			s.Strike = false
+		case p >= 30 && p <= 37, p >= 90 && p <= 97:
+			s.Fg = SgrAttr(Csi + strconv.Itoa(p) + "m")
+		case p == 39:
+			s.Fg = ""
+		case p >= 40 && p <= 47, p >= 100 && p <= 107:
+			s.Bg = SgrAttr(Csi + strconv.Itoa(p) + "m")
+		case p == 49:
+			s.Bg = ""
+		case p == 38, p == 48:
+			l := FG
+			if p == 48 {
+				l = BG
+			}
+			col, n, ok := extendedColor(l, params[i+1:])
+			i += n
+			if !ok {
+				continue
+			}
+			if l == FG {
+				s.Fg = col
+			} else {
+				s.Bg = col
+			}
+		}
+	}
+	return s
+}
+
+// ExtendedColor interprets the 8-bit or 24-bit color parameters following the
+// 38 or 48 SGR parameter. It returns the color, the number of parameters
+// consumed and whether the parameters were valid.
+func extendedColor(l Layer, params []int) (SgrAttr, int, bool) {
+	if len(params) == 0 {
+		return "", 0, false
+	}
+	switch params[0] {
+	case 5:
+		if len(params) < 2 {
+			return "", len(params), false
+		}
+		if !validUint8(params[1]) {
+			return "", 2, false
+		}
+		return Rgb8(l, uint8(params[1])), 2, true
+	case 2:
+		if len(params) < 4 {
+			return "", len(params), false
+		}
+		for _, c := range params[1:4] {
+			if !validUint8(c) {
+				return "", 4, false
+			}
+		}
+		return Rgb24(l, uint8(params[1]), uint8(params[2]), uint8(params[3])), 4, true
+	}
+	return "", 1, false
+}
+
+// DiffParams lists the SGR parameters that turn the from state into the to
+// state without resetting the terminal first.
+func diffParams(from, to State) []string {
+	var params []string
+	if (from.Bold && !to.Bold) || (from.Faint && !to.Faint) {
+		params = append(params, "22")
+		from.Bold, from.Faint = false, false
+	}
+	flags := [...]struct {
+		from, to bool
+		on, off  string
+	}{
+		{from.Bold, to.Bold, "1", ""},
+		{from.Faint, to.Faint, "2", ""},
+		{from.Italic, to.Italic, "3", "23"},
+		{from.Underline, to.Underline, "4", "24"},
+		{from.Blink, to.Blink, "5", "25"},
+		{from.Reverse, to.Reverse, "7", "27"},
+		{from.Hide, to.Hide, "8", "28"},
+		{from.Strike, to.Strike, "9", "29"},
+	}
+	for _, f := range flags {
+		switch {
+		case f.to && !f.from:
+			params = append(params, f.on)
+		case f.from && !f.to:
+			params = append(params, f.off)
+		}
+	}
+	params = appendColorParams(params, from.Fg, to.Fg, "39")
+	params = appendColorParams(params, from.Bg, to.Bg, "49")
+	return params
+}
+
+// AppendColorParams appends parameters of the to color if it differs from the
+// from color. The def parameter is used when to is the default color.
+func appendColorParams(params []string, from, to SgrAttr, def string) []string {
+	if from == to {
+		return params
+	}
+	if p, ok := sgrParams(to); ok {
+		return append(params, p)
+	}
+	return append(params, def)
+}
+
+// SgrParams returns the parameter substring of the SGR control sequence a.
+func sgrParams(a SgrAttr) (string, bool) {
+	s := string(a)
+	if !strings.HasPrefix(s, Csi) || !strings.HasSuffix(s, "m") {
+		return "", false
+	}
+	return s[len(Csi) : len(s)-1], true
+}
+
+// SplitParams splits the semicolon-separated parameter string into integers.
+// Empty parameters default to 0 and malformed ones are set to -1, so that
+// they are ignored.
+func splitParams(s string) []int {
+	if s == "" {
+		return []int{0}
+	}
+	fields := strings.Split(s, ";")
+	result := make([]int, len(fields))
+	for i, f := range fields {
+		if f == "" {
+			continue
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			n = -1
+		}
+		result[i] = n
+	}
+	return result
+}
@@ -0,0 +1,165 @@
+package termcols
+
+import (
+	"testing"
+)
+
+func TestNewState(t *testing.T) {
+	cases := []struct {
+		name  string
+		attrs []SgrAttr
+		exp   State
+	}{
+		{"empty", []SgrAttr{}, State{}},
+		{"bold", []SgrAttr{Bold}, State{Bold: true}},
+		{
+			"styles",
+			[]SgrAttr{Faint, Italic, Underline, Blink, Reverse, Hide, Strike},
+			State{
+				Faint:     true,
+				Italic:    true,
+				Underline: true,
+				Blink:     true,
+				Reverse:   true,
+				Hide:      true,
+				Strike:    true,
+			},
+		},
+		{"colors", []SgrAttr{BlueFg, RedBbg}, State{Fg: BlueFg, Bg: RedBbg}},
+		{"override", []SgrAttr{BlueFg, GreenBfg}, State{Fg: GreenBfg}},
+		{"defaults", []SgrAttr{BlueFg, WhiteBg, DefaultFg, DefaultBg}, State{}},
+		{"reset", []SgrAttr{Bold, RedFg, Reset, Italic}, State{Italic: true}},
+		{
+			"rgb",
+			[]SgrAttr{Rgb8(FG, 12), Rgb24(BG, 1, 2, 3)},
+			State{Fg: Rgb8(FG, 12), Bg: Rgb24(BG, 1, 2, 3)},
+		},
+		{
+			"combined",
+			[]SgrAttr{Csi + "1;38;5;200;48;2;10;20;30;4m"},
+			State{
+				Bold:      true,
+				Underline: true,
+				Fg:        Rgb8(FG, 200),
+				Bg:        Rgb24(BG, 10, 20, 30),
+			},
+		},
+		{
+			"switch-off",
+			[]SgrAttr{Bold, Faint, Italic, Strike, Csi + "22;23;29m"},
+			State{},
+		},
+		{"empty-params", []SgrAttr{Bold, Csi + "m"}, State{}},
+		{"invalid-rgb", []SgrAttr{Csi + "38;5;256;1m"}, State{Bold: true}},
+		{"truncated-rgb", []SgrAttr{Csi + "38;2;1;2m"}, State{}},
+		{"not-sgr", []SgrAttr{"bold", Csi + "2J"}, State{}},
+		{"default-style", []SgrAttr{DefaultStyle}, State{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if out := NewState(c.attrs...); out != c.exp {
+				t.Errorf("Have: %+v, want: %+v", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestReapply(t *testing.T) {
+	blueBg := NewState(BlueBg)
+	row := NewState(Bold, RedFg)
+	reset, blue := string(Reset), string(BlueBg)
+	cases := []struct {
+		name string
+		s    string
+		base State
+		exp  string
+	}{
+		{"plain", "abc", blueBg, "abc"},
+		{"no-base", "a" + reset + "b", State{}, "a" + reset + "b"},
+		{"reset", "a" + reset + "b", blueBg, "a" + reset + blue + "b"},
+		{"default-bg", "a\033[49mb", blueBg, "a\033[49m" + blue + "b"},
+		{"fg", string(RedFg) + "a", blueBg, string(RedFg) + "a"},
+		{"other-bg", string(RedBg) + "a" + reset, blueBg, string(RedBg) + "a" + reset + blue},
+		{"colorized", Colorize("ok", GreenFg) + "!", row, Colorize("ok", GreenFg) + "\033[1;31m!"},
+		{"bold-off", "a\033[22mb", row, "a\033[22m" + string(Bold) + "b"},
+		{"not-sgr", "a\033[2Kb", row, "a\033[2Kb"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if out := Reapply(c.s, c.base); out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestTransition(t *testing.T) {
+	cases := []struct {
+		name     string
+		from, to State
+		exp      SgrAttr
+	}{
+		{"same", State{Bold: true}, State{Bold: true}, ""},
+		{"zero-to-bold", State{}, State{Bold: true}, Csi + "1m"},
+		{"bold-to-zero", State{Bold: true}, State{}, Csi + "0m"},
+		{
+			"fg-change",
+			State{Bold: true, Fg: RedFg},
+			State{Bold: true, Fg: BlueFg},
+			Csi + "34m",
+		},
+		{
+			"bold-off-fg-change",
+			State{Bold: true, Italic: true, Fg: RedFg},
+			State{Italic: true, Fg: BlueFg},
+			Csi + "22;34m",
+		},
+		{
+			"bold-to-faint",
+			State{Bold: true, Underline: true},
+			State{Faint: true, Underline: true},
+			Csi + "22;2m",
+		},
+		{
+			"faint-off-keeps-bold",
+			State{Bold: true, Faint: true, Fg: Rgb24(FG, 100, 100, 100)},
+			State{Bold: true, Fg: Rgb24(FG, 100, 100, 100)},
+			Csi + "22;1m",
+		},
+		{
+			"reset-is-shorter",
+			State{Italic: true, Underline: true, Fg: RedFg},
+			State{Bold: true},
+			Csi + "0;1m",
+		},
+		{
+			"default-colors",
+			State{Fg: RedFg, Bg: Rgb8(BG, 12), Italic: true, Strike: true},
+			State{Italic: true, Strike: true},
+			Csi + "39;49m",
+		},
+		{
+			"rgb",
+			State{Reverse: true},
+			State{Reverse: true, Fg: Rgb24(FG, 1, 2, 3), Bg: Rgb8(BG, 4)},
+			Csi + "38;2;1;2;3;48;5;4m",
+		},
+		{
+			"styles-off",
+			State{Italic: true, Underline: true, Blink: true, Fg: RedFg},
+			State{Blink: true, Fg: RedFg},
+			Csi + "23;24m",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := Transition(c.from, c.to)
+			if out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+			if s := c.from.Apply(out); s != c.to {
+				t.Errorf("Have: %+v, want: %+v", s, c.to)
+			}
+		})
+	}
+}
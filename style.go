@@ -0,0 +1,149 @@
+package termcols
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Style groups SGR attributes that are applied to text together. With Compact
+// set, the attributes are rendered as a single control sequence combined with
+// [Combine] rather than one control sequence per attribute, which cuts the
+// size of heavily styled output.
+type Style struct {
+	Attrs   []SgrAttr
+	Compact bool
+}
+
+// NewStyle returns the Style applying attrs in sequence.
+func NewStyle(attrs ...SgrAttr) Style {
+	return Style{Attrs: attrs}
+}
+
+// Sequence returns the control sequences that turn the style on.
+func (s Style) Sequence() SgrAttr {
+	if s.Compact {
+		return Combine(s.Attrs...)
+	}
+	return concat(s.Attrs)
+}
+
+// Render returns the string text styled the same way as with [Colorize].
+func (s Style) Render(text string) string {
+	return Colorize(text, s.attrs()...)
+}
+
+// Append appends the string text styled the same way as with [AppendColorize]
+// to dst and returns the extended buffer.
+func (s Style) Append(dst []byte, text string) []byte {
+	return AppendColorize(dst, text, s.attrs()...)
+}
+
+// Attrs returns the attributes of the style to be rendered.
+func (s Style) attrs() []SgrAttr {
+	if !s.Compact || len(s.Attrs) < 2 {
+		return s.Attrs
+	}
+	return []SgrAttr{Combine(s.Attrs...)}
+}
+
+// Params returns the numeric parameters of the SGR control sequence a, so
+// that, for instance, Rgb24(BG, 1, 2, 3) yields 48, 2, 1, 2 and 3. Empty
+// parameters stand for 0 the way terminals interpret them. It returns false
+// when a is not a well-formed SGR control sequence.
+func (a SgrAttr) Params() ([]int, bool) {
+	s, ok := sgrParams(a)
+	if !ok {
+		return nil, false
+	}
+	params := splitParams(s)
+	for _, p := range params {
+		if p < 0 {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// Split splits the SGR control sequence a into one attribute for each of its
+// parameters, keeping 8-bit and 24-bit colors together with their 38 or 48
+// parameter, so that CSI 1;38;5;9m yields CSI 1m and CSI 38;5;9m. Each part
+// can then be named with Name or OffName. Empty parameters stand for 0, and
+// malformed parameters and incomplete colors make up parts of their own that
+// neither of them names. It returns false when a is not an SGR control
+// sequence.
+func (a SgrAttr) Split() ([]SgrAttr, bool) {
+	s, ok := sgrParams(a)
+	if !ok {
+		return nil, false
+	}
+	fields := strings.Split(s, ";")
+	for i, f := range fields {
+		if f == "" {
+			fields[i] = "0"
+		}
+	}
+	params := splitParams(s)
+	var result []SgrAttr
+	for i := 0; i < len(fields); i++ {
+		n := 1
+		if params[i] == 38 || params[i] == 48 {
+			_, m, _ := extendedColor(FG, params[i+1:])
+			n += m
+		}
+		result = append(result, SgrAttr(Csi+strings.Join(fields[i:i+n], ";")+"m"))
+		i += n - 1
+	}
+	return result, true
+}
+
+// Combine merges attrs into a single SGR control sequence carrying all their
+// parameters in order, so that Combine(Bold, BlueFg, Rgb24(BG, 1, 2, 3))
+// yields CSI 1;34;48;2;1;2;3m. The effect on the terminal is the same as that
+// of attrs written one after another. Should any of attrs not be a
+// well-formed SGR control sequence, or carry an incomplete 8-bit or 24-bit
+// color that would swallow the parameters of the next attribute, attrs are
+// concatenated as they are.
+func Combine(attrs ...SgrAttr) SgrAttr {
+	if len(attrs) == 0 {
+		return ""
+	}
+	b := []byte(Csi)
+	for i, a := range attrs {
+		params, ok := a.Params()
+		if !ok || !completeColors(params) {
+			return concat(attrs)
+		}
+		for j, p := range params {
+			if i > 0 || j > 0 {
+				b = append(b, ';')
+			}
+			b = strconv.AppendInt(b, int64(p), 10)
+		}
+	}
+	return SgrAttr(append(b, 'm'))
+}
+
+// CompleteColors reports whether each 38 and 48 parameter among params is
+// followed by all the parameters of an 8-bit or 24-bit color.
+func completeColors(params []int) bool {
+	for i := 0; i < len(params); i++ {
+		if params[i] != 38 && params[i] != 48 {
+			continue
+		}
+		_, n, ok := extendedColor(FG, params[i+1:])
+		if !ok {
+			return false
+		}
+		i += n
+	}
+	return true
+}
+
+// Concat concatenates attrs into a single SgrAttr.
+func concat(attrs []SgrAttr) SgrAttr {
+	var b strings.Builder
+	for _, a := range attrs {
+		b.WriteString(string(a))
+	}
+	return SgrAttr(b.String())
+}
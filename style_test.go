@@ -0,0 +1,138 @@
+package termcols
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParams(t *testing.T) {
+	cases := []struct {
+		attr SgrAttr
+		exp  []int
+		ok   bool
+	}{
+		{Bold, []int{1}, true},
+		{Reset, []int{0}, true},
+		{BlueBbg, []int{104}, true},
+		{Rgb8(FG, 214), []int{38, 5, 214}, true},
+		{Rgb24(BG, 1, 2, 3), []int{48, 2, 1, 2, 3}, true},
+		{Csi + "m", []int{0}, true},
+		{Csi + "1;;4m", []int{1, 0, 4}, true},
+
+		{"", nil, false},
+		{"bold", nil, false},
+		{Csi + "1;xm", nil, false},
+		{Csi + "2J", nil, false},
+		{Bold + Italic, nil, false},
+	}
+	for _, c := range cases {
+		t.Run(string(c.attr), func(t *testing.T) {
+			out, ok := c.attr.Params()
+			if !reflect.DeepEqual(out, c.exp) || ok != c.ok {
+				t.Errorf("Have: %v %t, want: %v %t", out, ok, c.exp, c.ok)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	cases := []struct {
+		attr SgrAttr
+		exp  []SgrAttr
+		ok   bool
+	}{
+		{Bold, []SgrAttr{Bold}, true},
+		{Csi + "m", []SgrAttr{Reset}, true},
+		{Csi + "1;;4m", []SgrAttr{Bold, Reset, Underline}, true},
+		{Combine(Bold, Rgb8(FG, 9), Rgb24(BG, 1, 2, 3), BlueFg), []SgrAttr{Bold, Rgb8(FG, 9), Rgb24(BG, 1, 2, 3), BlueFg}, true},
+		{Csi + "38;5;300;1m", []SgrAttr{Csi + "38;5;300m", Bold}, true},
+		{Csi + "38;7;1m", []SgrAttr{Csi + "38;7m", Bold}, true},
+		{Csi + "48;2;1m", []SgrAttr{Csi + "48;2;1m"}, true},
+		{Csi + "38m", []SgrAttr{Csi + "38m"}, true},
+		{Csi + "1:2;3m", []SgrAttr{Csi + "1:2m", Italic}, true},
+
+		{"", nil, false},
+		{"bold", nil, false},
+		{Csi + "2J", nil, false},
+	}
+	for _, c := range cases {
+		t.Run(string(c.attr), func(t *testing.T) {
+			out, ok := c.attr.Split()
+			if !reflect.DeepEqual(out, c.exp) || ok != c.ok {
+				t.Errorf("Have: %q %t, want: %q %t", out, ok, c.exp, c.ok)
+			}
+		})
+	}
+}
+
+func TestCombine(t *testing.T) {
+	cases := []struct {
+		attrs []SgrAttr
+		exp   SgrAttr
+	}{
+		{[]SgrAttr{}, ""},
+		{[]SgrAttr{Bold}, Bold},
+		{[]SgrAttr{Bold, BlueFg, Rgb24(BG, 1, 2, 3)}, Csi + "1;34;48;2;1;2;3m"},
+		{[]SgrAttr{Reset, Italic, Rgb8(FG, 9)}, Csi + "0;3;38;5;9m"},
+		{[]SgrAttr{Bold, "bold"}, Bold + "bold"},
+		{[]SgrAttr{Bold, Csi + "2J"}, Bold + Csi + "2J"},
+		{[]SgrAttr{Csi + "38;5m", Bold}, Csi + "38;5m" + Bold},
+		{[]SgrAttr{Italic, Csi + "48;2;1m", BlueFg}, Italic + Csi + "48;2;1m" + BlueFg},
+		{[]SgrAttr{Csi + "38m", Bold}, Csi + "38m" + Bold},
+		{[]SgrAttr{Csi + "38;5;300m", Bold}, Csi + "38;5;300m" + Bold},
+		{[]SgrAttr{Csi + "1;38;5;9;48;2;1;2;3m", Italic}, Csi + "1;38;5;9;48;2;1;2;3;3m"},
+	}
+	for _, c := range cases {
+		t.Run(string(c.exp), func(t *testing.T) {
+			out := Combine(c.attrs...)
+			if out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+			if _, ok := out.Params(); ok && NewState(out) != NewState(c.attrs...) {
+				t.Errorf("Have: %+v, want: %+v", NewState(out), NewState(c.attrs...))
+			}
+		})
+	}
+}
+
+func TestStyle(t *testing.T) {
+	cases := []struct {
+		style  Style
+		seq    SgrAttr
+		expOut string
+	}{
+		{
+			NewStyle(),
+			"",
+			"text",
+		},
+		{
+			NewStyle(Bold, BlueFg),
+			Bold + BlueFg,
+			"\033[1m\033[34mtext\033[0m",
+		},
+		{
+			Style{Attrs: []SgrAttr{Bold, BlueFg, Rgb24(BG, 1, 2, 3)}, Compact: true},
+			Csi + "1;34;48;2;1;2;3m",
+			"\033[1;34;48;2;1;2;3mtext\033[0m",
+		},
+		{
+			Style{Compact: true},
+			"",
+			"text",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.expOut, func(t *testing.T) {
+			if seq := c.style.Sequence(); seq != c.seq {
+				t.Errorf("Have: %q, want: %q", seq, c.seq)
+			}
+			if out := c.style.Render("text"); out != c.expOut {
+				t.Errorf("Have: %q, want: %q", out, c.expOut)
+			}
+			if out := c.style.Append([]byte("> "), "text"); string(out) != "> "+c.expOut {
+				t.Errorf("Have: %q, want: %q", out, "> "+c.expOut)
+			}
+		})
+	}
+}
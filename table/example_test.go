@@ -0,0 +1,23 @@
+package table_test
+
+import (
+	"fmt"
+
+	"github.com/mdm-code/termcols/table"
+)
+
+// ExampleTable shows how to render a table with a right-aligned column.
+func ExampleTable() {
+	t := table.New("Name", "Status", "Took")
+	t.AddRow("build", "ok", "1.2s")
+	t.AddRow("test", "failed", "12.9s")
+	t.SetAlign(2, table.Right)
+	fmt.Print(t)
+	// Output:
+	// ┌───────┬────────┬───────┐
+	// │ Name  │ Status │  Took │
+	// ├───────┼────────┼───────┤
+	// │ build │ ok     │  1.2s │
+	// │ test  │ failed │ 12.9s │
+	// └───────┴────────┴───────┘
+}
@@ -0,0 +1,360 @@
+/*
+Package table renders tables with colored cells on the terminal. Cells are
+measured by their visible width with termcols.Width, so SGR control sequences
+inside cells do not break the alignment of columns.
+
+A Table has an optional header and any number of rows, and it supports
+per-column alignment, styles of single cells, whole rows and whole columns,
+zebra striping, several Unicode and ASCII border styles and shrinking columns
+to fit the width of the terminal. Cells that do not fit are truncated with an
+ellipsis. Cell styles carry on past the resets of colorized text inside cells.
+
+# Usage
+
+	package main
+
+	import (
+		"fmt"
+
+		"github.com/mdm-code/termcols"
+		"github.com/mdm-code/termcols/table"
+	)
+
+	func main() {
+		t := table.New("Name", "Status", "Took")
+		t.AddRow("build", termcols.Colorize("ok", termcols.GreenFg), "1.2s")
+		t.AddRow("test", termcols.Colorize("failed", termcols.RedFg), "12.9s")
+		t.SetAlign(2, table.Right)
+		t.SetBorder(table.Rounded)
+		fmt.Print(t)
+	}
+*/
+package table
+
+import (
+	"io"
+	"strings"
+
+	"github.com/mdm-code/termcols"
+)
+
+// Column alignments
+const (
+	Left Align = iota
+	Right
+	Center
+)
+
+// Ellipsis replaces the part of a cell cut off when its column is shrunk.
+const Ellipsis = "…"
+
+// Border styles
+var (
+	None     = Border{}
+	ASCII    = Border{"-", "|", "+", "+", "+", "+", "+", "+", "+", "+", "+"}
+	Light    = Border{"─", "│", "┌", "┬", "┐", "├", "┼", "┤", "└", "┴", "┘"}
+	Rounded  = Border{"─", "│", "╭", "┬", "╮", "├", "┼", "┤", "╰", "┴", "╯"}
+	Heavy    = Border{"━", "┃", "┏", "┳", "┓", "┣", "╋", "┫", "┗", "┻", "┛"}
+	Double   = Border{"═", "║", "╔", "╦", "╗", "╠", "╬", "╣", "╚", "╩", "╝"}
+	Markdown = Border{"-", "|", "", "", "", "|", "|", "|", "", "", ""}
+)
+
+// Align is the horizontal alignment of cells in a column.
+type Align int
+
+// Border holds the strings that make up the lines around and between cells,
+// each one taking up a single terminal cell. Horizontal rules whose left
+// corner is empty are left out, so the None border draws no rules at all and
+// the Markdown border draws only the one below the header. Without Vertical,
+// columns are separated by spaces only.
+type Border struct {
+	Horizontal string
+	Vertical   string
+
+	TopLeft  string
+	TopMid   string
+	TopRight string
+
+	MidLeft  string
+	MidMid   string
+	MidRight string
+
+	BottomLeft  string
+	BottomMid   string
+	BottomRight string
+}
+
+// Table is a table of text cells. The zero value is an empty table without a
+// header. Cells can contain SGR control sequences and line breaks, in which
+// case the row takes up several lines.
+type Table struct {
+	header      []string
+	rows        [][]string
+	align       map[int]Align
+	border      Border
+	borderStyle []termcols.SgrAttr
+	headerStyle []termcols.SgrAttr
+	zebra       []termcols.SgrAttr
+	colStyles   map[int][]termcols.SgrAttr
+	rowStyles   map[int][]termcols.SgrAttr
+	cellStyles  map[[2]int][]termcols.SgrAttr
+	maxWidth    int
+}
+
+// New returns a Table with the given header and the Light border. The table
+// has no header when none is given.
+func New(header ...string) *Table {
+	return &Table{header: header, border: Light}
+}
+
+// AddRow appends a row of cells to the table. Rows can have different
+// numbers of cells, and missing cells are left blank.
+func (t *Table) AddRow(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// SetAlign sets the alignment of cells in the column col, including its
+// header. Columns are aligned to the left by default.
+func (t *Table) SetAlign(col int, a Align) {
+	if t.align == nil {
+		t.align = make(map[int]Align)
+	}
+	t.align[col] = a
+}
+
+// SetBorder sets the border style of the table.
+func (t *Table) SetBorder(b Border) {
+	t.border = b
+}
+
+// SetBorderStyle sets attrs applied to borders.
+func (t *Table) SetBorderStyle(attrs ...termcols.SgrAttr) {
+	t.borderStyle = attrs
+}
+
+// SetHeaderStyle sets attrs applied to header cells.
+func (t *Table) SetHeaderStyle(attrs ...termcols.SgrAttr) {
+	t.headerStyle = attrs
+}
+
+// SetZebra sets attrs applied to every other row starting from the second
+// one, such as a background color that makes long rows easier to follow.
+func (t *Table) SetZebra(attrs ...termcols.SgrAttr) {
+	t.zebra = attrs
+}
+
+// SetColumnStyle sets attrs applied to cells of the column col.
+func (t *Table) SetColumnStyle(col int, attrs ...termcols.SgrAttr) {
+	if t.colStyles == nil {
+		t.colStyles = make(map[int][]termcols.SgrAttr)
+	}
+	t.colStyles[col] = attrs
+}
+
+// SetRowStyle sets attrs applied to cells of the row row, counted from 0
+// without the header.
+func (t *Table) SetRowStyle(row int, attrs ...termcols.SgrAttr) {
+	if t.rowStyles == nil {
+		t.rowStyles = make(map[int][]termcols.SgrAttr)
+	}
+	t.rowStyles[row] = attrs
+}
+
+// SetCellStyle sets attrs applied to the cell in the column col of the row
+// row, counted from 0 without the header.
+func (t *Table) SetCellStyle(row, col int, attrs ...termcols.SgrAttr) {
+	if t.cellStyles == nil {
+		t.cellStyles = make(map[[2]int][]termcols.SgrAttr)
+	}
+	t.cellStyles[[2]int{row, col}] = attrs
+}
+
+// SetMaxWidth sets the maximum width of the table in terminal cells, usually
+// the width of the terminal. The widest columns are shrunk until the table
+// fits, and cells that no longer fit are truncated with Ellipsis. A width of
+// 0 means no limit.
+func (t *Table) SetMaxWidth(width int) {
+	t.maxWidth = width
+}
+
+// Render returns the table as a string with each line ended with a line
+// break. Styles are applied in the order of column, zebra, row and cell
+// styles, so that the more specific ones take precedence, and they cover
+// the padding around cell contents.
+func (t *Table) Render() string {
+	widths := t.widths()
+	if len(widths) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	last := len(t.rows) - 1
+	t.rule(&b, widths, t.border.TopLeft, t.border.TopMid, t.border.TopRight)
+	if len(t.header) > 0 {
+		t.row(&b, widths, t.header, func(int) []termcols.SgrAttr { return t.headerStyle })
+		if last >= 0 {
+			t.rule(&b, widths, t.border.MidLeft, t.border.MidMid, t.border.MidRight)
+		}
+	}
+	for i, cells := range t.rows {
+		t.row(&b, widths, cells, func(col int) []termcols.SgrAttr { return t.style(i, col) })
+	}
+	t.rule(&b, widths, t.border.BottomLeft, t.border.BottomMid, t.border.BottomRight)
+	return b.String()
+}
+
+// String returns the table rendered with Render.
+func (t *Table) String() string {
+	return t.Render()
+}
+
+// WriteTo writes the rendered table to w.
+func (t *Table) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, t.Render())
+	return int64(n), err
+}
+
+// Widths returns the widths of columns shrunk to fit the maximum width.
+func (t *Table) widths() []int {
+	var widths []int
+	measure := func(cells []string) {
+		for i, c := range cells {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			for _, l := range strings.Split(c, "\n") {
+				widths[i] = max(widths[i], termcols.Width(l))
+			}
+		}
+	}
+	measure(t.header)
+	for _, r := range t.rows {
+		measure(r)
+	}
+	if t.maxWidth <= 0 {
+		return widths
+	}
+	// NOTE: Columns are shrunk one cell at a time starting from the widest one,
+	// which keeps narrow columns, such as numbers, intact for as long as
+	// possible.
+	for total := t.width(widths); total > t.maxWidth; total-- {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= 1 {
+			break
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+// Width returns the total width of the table with columns of widths.
+func (t *Table) width(widths []int) int {
+	n := 0
+	for i, w := range widths {
+		l, r := t.padding(i, len(widths))
+		n += l + w + r
+	}
+	if t.border.Vertical != "" {
+		n += len(widths) + 1
+	}
+	return n
+}
+
+// Padding returns the number of spaces on the left and the right side of
+// cells in the column col out of n columns. Borderless tables have no padding
+// on their outer edges.
+func (t *Table) padding(col, n int) (int, int) {
+	l, r := 1, 1
+	if t.border.Vertical == "" {
+		if col == 0 {
+			l = 0
+		}
+		if col == n-1 {
+			r = 0
+		}
+	}
+	return l, r
+}
+
+// Style returns the attributes applied to the cell in the column col of the
+// row row.
+func (t *Table) style(row, col int) []termcols.SgrAttr {
+	var attrs []termcols.SgrAttr
+	attrs = append(attrs, t.colStyles[col]...)
+	if row%2 == 1 {
+		attrs = append(attrs, t.zebra...)
+	}
+	attrs = append(attrs, t.rowStyles[row]...)
+	return append(attrs, t.cellStyles[[2]int{row, col}]...)
+}
+
+// Rule writes a horizontal rule with the left, mid and right corners to b.
+func (t *Table) rule(b *strings.Builder, widths []int, left, mid, right string) {
+	if t.border.Horizontal == "" || left == "" {
+		return
+	}
+	var line strings.Builder
+	line.WriteString(left)
+	for i, w := range widths {
+		if i > 0 {
+			line.WriteString(mid)
+		}
+		l, r := t.padding(i, len(widths))
+		line.WriteString(strings.Repeat(t.border.Horizontal, l+w+r))
+	}
+	line.WriteString(right)
+	b.WriteString(termcols.Colorize(line.String(), t.borderStyle...))
+	b.WriteByte('\n')
+}
+
+// Row writes the cells of a single row to b, one line at a time. The style
+// function returns attributes of the cell in a given column.
+func (t *Table) row(b *strings.Builder, widths []int, cells []string, style func(int) []termcols.SgrAttr) {
+	lines := make([][]string, len(widths))
+	height := 1
+	for i := range widths {
+		if i < len(cells) {
+			lines[i] = strings.Split(cells[i], "\n")
+		}
+		height = max(height, len(lines[i]))
+	}
+	sep := t.border.Vertical
+	if sep != "" {
+		sep = termcols.Colorize(sep, t.borderStyle...)
+	}
+	for h := 0; h < height; h++ {
+		b.WriteString(sep)
+		for i, w := range widths {
+			if i > 0 {
+				b.WriteString(sep)
+			}
+			var s string
+			if h < len(lines[i]) {
+				s = termcols.Truncate(lines[i][h], w, Ellipsis)
+			}
+			s = t.alignText(s, w, i)
+			l, r := t.padding(i, len(widths))
+			s = strings.Repeat(" ", l) + s + strings.Repeat(" ", r)
+			attrs := style(i)
+			s = termcols.Reapply(s, termcols.NewState(attrs...))
+			b.WriteString(termcols.Colorize(s, attrs...))
+		}
+		b.WriteString(sep)
+		b.WriteByte('\n')
+	}
+}
+
+// AlignText pads the string s to width the way the column col is aligned.
+func (t *Table) alignText(s string, width, col int) string {
+	switch t.align[col] {
+	case Right:
+		return termcols.PadLeft(s, width)
+	case Center:
+		return termcols.Center(s, width)
+	}
+	return termcols.PadRight(s, width)
+}
@@ -0,0 +1,272 @@
+package table
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mdm-code/termcols"
+)
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		name  string
+		build func() *Table
+		exp   string
+	}{
+		{
+			"empty",
+			func() *Table { return New() },
+			"",
+		},
+		{
+			"header-only",
+			func() *Table { return New("a", "b") },
+			"┌───┬───┐\n│ a │ b │\n└───┴───┘\n",
+		},
+		{
+			"no-header",
+			func() *Table {
+				t := New()
+				t.AddRow("a", "b")
+				return t
+			},
+			"┌───┬───┐\n│ a │ b │\n└───┴───┘\n",
+		},
+		{
+			"ascii",
+			func() *Table {
+				t := New("id", "name")
+				t.AddRow("1", "x")
+				t.SetBorder(ASCII)
+				return t
+			},
+			"+----+------+\n| id | name |\n+----+------+\n| 1  | x    |\n+----+------+\n",
+		},
+		{
+			"none",
+			func() *Table {
+				t := New("id", "name")
+				t.AddRow("1", "x")
+				t.SetBorder(None)
+				return t
+			},
+			"id  name\n1   x   \n",
+		},
+		{
+			"markdown",
+			func() *Table {
+				t := New("id", "name")
+				t.AddRow("1", "x")
+				t.SetBorder(Markdown)
+				return t
+			},
+			"| id | name |\n|----|------|\n| 1  | x    |\n",
+		},
+		{
+			"align",
+			func() *Table {
+				t := New("left", "right", "center")
+				t.AddRow("a", "b", "c")
+				t.SetAlign(1, Right)
+				t.SetAlign(2, Center)
+				t.SetBorder(ASCII)
+				return t
+			},
+			"+------+-------+--------+\n" +
+				"| left | right | center |\n" +
+				"+------+-------+--------+\n" +
+				"| a    |     b |   c    |\n" +
+				"+------+-------+--------+\n",
+		},
+		{
+			"ragged",
+			func() *Table {
+				t := New("a")
+				t.AddRow("1", "2")
+				t.SetBorder(ASCII)
+				return t
+			},
+			"+---+---+\n| a |   |\n+---+---+\n| 1 | 2 |\n+---+---+\n",
+		},
+		{
+			"multi-line",
+			func() *Table {
+				t := New()
+				t.AddRow("a\nbb", "c")
+				t.SetBorder(ASCII)
+				return t
+			},
+			"+----+---+\n| a  | c |\n| bb |   |\n+----+---+\n",
+		},
+		{
+			"colored",
+			func() *Table {
+				t := New("status")
+				t.AddRow(termcols.Colorize("ok", termcols.GreenFg))
+				t.SetBorder(Markdown)
+				return t
+			},
+			"| status |\n|--------|\n| " + termcols.Colorize("ok", termcols.GreenFg) + "     |\n",
+		},
+		{
+			"wide",
+			func() *Table {
+				t := New("名前")
+				t.AddRow("a")
+				t.SetBorder(Markdown)
+				return t
+			},
+			"| 名前 |\n|------|\n| a    |\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if out := c.build().Render(); out != c.exp {
+				t.Errorf("Have:\n%s\nwant:\n%s", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestMaxWidth(t *testing.T) {
+	cases := []struct {
+		name  string
+		width int
+		exp   string
+	}{
+		{"unlimited", 0, "| id | description  |\n|----|--------------|\n| 1  | a long story |\n"},
+		{"fits", 21, "| id | description  |\n|----|--------------|\n| 1  | a long story |\n"},
+		{"shrink", 17, "| id | descrip… |\n|----|----------|\n| 1  | a long … |\n"},
+		{"shrink-all", 12, "| id | de… |\n|----|-----|\n| 1  | a … |\n"},
+		{"minimum", 1, "| … | … |\n|---|---|\n| 1 | … |\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tb := New("id", "description")
+			tb.AddRow("1", "a long story")
+			tb.SetBorder(Markdown)
+			tb.SetMaxWidth(c.width)
+			out := tb.Render()
+			if out != c.exp {
+				t.Errorf("Have:\n%s\nwant:\n%s", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestStyles(t *testing.T) {
+	tb := New("h")
+	for i := 0; i < 4; i++ {
+		tb.AddRow("x")
+	}
+	tb.SetBorder(None)
+	tb.SetHeaderStyle(termcols.Bold)
+	tb.SetColumnStyle(0, termcols.RedFg)
+	tb.SetZebra(termcols.BlackBg)
+	tb.SetRowStyle(2, termcols.Italic)
+	tb.SetCellStyle(3, 0, termcols.BlueFg)
+	exp := []string{
+		termcols.Colorize("h", termcols.Bold),
+		termcols.Colorize("x", termcols.RedFg),
+		termcols.Colorize("x", termcols.RedFg, termcols.BlackBg),
+		termcols.Colorize("x", termcols.RedFg, termcols.Italic),
+		termcols.Colorize("x", termcols.RedFg, termcols.BlackBg, termcols.BlueFg),
+	}
+	out := strings.Split(strings.TrimSuffix(tb.Render(), "\n"), "\n")
+	if len(out) != len(exp) {
+		t.Fatalf("Have: %d lines, want: %d", len(out), len(exp))
+	}
+	for i := range exp {
+		if out[i] != exp[i] {
+			t.Errorf("Have: %q, want: %q", out[i], exp[i])
+		}
+	}
+}
+
+func TestStyledCells(t *testing.T) {
+	green, reset := string(termcols.GreenFg), string(termcols.Reset)
+	cases := []struct {
+		name  string
+		style func(tb *Table)
+		cell  string
+		exp   string
+	}{
+		{
+			"zebra",
+			func(tb *Table) { tb.SetZebra(termcols.BlueBg) },
+			termcols.Colorize("ok", termcols.GreenFg),
+			string(termcols.BlueBg) + green + "ok" + reset + string(termcols.BlueBg) + "  " + reset,
+		},
+		{
+			"row",
+			func(tb *Table) { tb.SetRowStyle(1, termcols.Bold, termcols.RedFg) },
+			termcols.Colorize("ok", termcols.GreenFg) + "!",
+			string(termcols.Bold) + string(termcols.RedFg) + green + "ok" + reset + "\033[1;31m! " + reset,
+		},
+		{
+			"cell-keeps-own",
+			func(tb *Table) { tb.SetCellStyle(1, 0, termcols.BlueBg) },
+			green + "ok" + string(termcols.DefaultFg) + " ",
+			string(termcols.BlueBg) + green + "ok" + string(termcols.DefaultFg) + "  " + reset,
+		},
+		{
+			"truncated",
+			func(tb *Table) { tb.SetZebra(termcols.BlueBg) },
+			green + "okay" + "!!",
+			string(termcols.BlueBg) + green + "oka…" + reset + string(termcols.BlueBg) + reset,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tb := New("head")
+			tb.AddRow("x")
+			tb.AddRow(c.cell)
+			tb.SetBorder(None)
+			tb.SetMaxWidth(4)
+			c.style(tb)
+			out := strings.Split(tb.Render(), "\n")[2]
+			if out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestBorderStyle(t *testing.T) {
+	tb := New("a")
+	tb.SetBorder(ASCII)
+	tb.SetBorderStyle(termcols.BlueFg)
+	rule, bar := termcols.Colorize("+---+", termcols.BlueFg), termcols.Colorize("|", termcols.BlueFg)
+	exp := rule + "\n" + bar + " a " + bar + "\n" + rule + "\n"
+	if out := tb.Render(); out != exp {
+		t.Errorf("Have: %q, want: %q", out, exp)
+	}
+
+	// NOTE: Without borders the style has nothing to color.
+	tb = New("a", "b")
+	tb.SetBorder(None)
+	tb.SetBorderStyle(termcols.RedFg)
+	if out, exp := tb.Render(), "a  b\n"; out != exp {
+		t.Errorf("Have: %q, want: %q", out, exp)
+	}
+}
+
+// errWriter fails every write with errWrite.
+type errWriter struct{}
+
+var errWrite = errors.New("Write error")
+
+func (errWriter) Write([]byte) (int, error) { return 0, errWrite }
+
+func TestWriteTo(t *testing.T) {
+	tb := New("a")
+	var b strings.Builder
+	n, err := tb.WriteTo(&b)
+	if err != nil || n != int64(b.Len()) || b.String() != tb.String() {
+		t.Errorf("Have: %d %v %q, want: %d nil %q", n, err, b.String(), b.Len(), tb.String())
+	}
+	if _, err := tb.WriteTo(errWriter{}); err != errWrite {
+		t.Errorf("Have: %v, want: %v", err, errWrite)
+	}
+}
@@ -2,8 +2,9 @@ package termcols
 
 import (
 	"fmt"
+	"io"
 	"strings"
-	"unsafe"
+	"sync"
 )
 
 const (
@@ -15,6 +16,12 @@ const (
 
 	// Csi stands for Control Sequence Introducer
 	Csi = Esc + "["
+
+	// Osc stands for Operating System Command
+	Osc = Esc + "]"
+
+	// St stands for String Terminator that ends OSC control sequences
+	St = Esc + "\\"
 )
 
 // Layer
@@ -101,6 +108,16 @@ type SgrAttr string
 // implemented in [Rgb8] and [Rgb24] public functions respectively.
 type Layer string
 
+// MaxPooledBuf caps the capacity of buffers returned to bufPool.
+const maxPooledBuf = 64 * 1024
+
+var bufPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 256)
+		return &b
+	},
+}
+
 // Colorize returns a string literal s with attrs SGR control sequences
 // prepended and the reset control sequence appended at the end. The sequence
 // of attrs passed to the function call is preserved, so colors and styles can
@@ -109,8 +126,54 @@ func Colorize(s string, attrs ...SgrAttr) string {
 	if len(attrs) == 0 {
 		return s
 	}
-	prefix := *(*[]string)(unsafe.Pointer(&attrs))
-	return strings.Join(prefix, "") + s + string(Reset)
+	var b strings.Builder
+	b.Grow(colorizedLen(s, attrs))
+	for _, a := range attrs {
+		b.WriteString(string(a))
+	}
+	b.WriteString(s)
+	b.WriteString(string(Reset))
+	return b.String()
+}
+
+// AppendColorize appends the string s colorized the same way as with
+// [Colorize] to dst and returns the extended buffer. It does not allocate
+// when dst has enough spare capacity.
+func AppendColorize(dst []byte, s string, attrs ...SgrAttr) []byte {
+	if len(attrs) == 0 {
+		return append(dst, s...)
+	}
+	for _, a := range attrs {
+		dst = append(dst, a...)
+	}
+	dst = append(dst, s...)
+	return append(dst, Reset...)
+}
+
+// WriteColorized writes the string s colorized the same way as with
+// [Colorize] to w with a single call to its Write method. It returns the
+// number of bytes written and any error encountered by w. Buffers are reused
+// across calls, so it does not allocate in the steady state.
+func WriteColorized(w io.Writer, s string, attrs ...SgrAttr) (int, error) {
+	bp := bufPool.Get().(*[]byte)
+	b := AppendColorize((*bp)[:0], s, attrs...)
+	n, err := w.Write(b)
+	// NOTE: Unusually large buffers are dropped so that a single long
+	// string does not keep its memory pinned in the pool.
+	if cap(b) <= maxPooledBuf {
+		*bp = b
+		bufPool.Put(bp)
+	}
+	return n, err
+}
+
+// ColorizedLen returns the length of the string s colorized with attrs.
+func colorizedLen(s string, attrs []SgrAttr) int {
+	n := len(s) + len(Reset)
+	for _, a := range attrs {
+		n += len(a)
+	}
+	return n
 }
 
 // Rgb8 returns the set foreground/background 8-bit color control sequence. It
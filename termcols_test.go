@@ -1,6 +1,9 @@
 package termcols
 
 import (
+	"errors"
+	"io"
+	"strings"
 	"testing"
 )
 
@@ -9,13 +12,13 @@ var (
 	benchResult string
 )
 
-// BenchmarkColorize was arranged to test the performance of the slice type
-// conversion using unsafe.Pointer as opposed to looping over the source
-// slice and appending elements one after another to the target slice.
-//
-// In my testing slice type conversion with unsafe.Pointer is almost four
-// times faster than the memory-safe alternative.
+// NOTE: benchBytes is added to avoid compiler optimization
+var benchBytes []byte
+
+// BenchmarkColorize measures Colorize that builds the whole colorized string
+// with a single allocation.
 func BenchmarkColorize(b *testing.B) {
+	b.ReportAllocs()
 	var s string
 	for i := 0; i < b.N; i++ {
 		s = Colorize("Colorize me!", BlackFg)
@@ -23,6 +26,26 @@ func BenchmarkColorize(b *testing.B) {
 	benchResult = s
 }
 
+// BenchmarkAppendColorize measures AppendColorize that reuses the buffer and
+// does not allocate at all.
+func BenchmarkAppendColorize(b *testing.B) {
+	b.ReportAllocs()
+	buf := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		buf = AppendColorize(buf[:0], "Colorize me!", Bold, BlackFg)
+	}
+	benchBytes = buf
+}
+
+// BenchmarkWriteColorized measures WriteColorized that does not allocate in
+// the steady state.
+func BenchmarkWriteColorized(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		WriteColorized(io.Discard, "Colorize me!", Bold, BlackFg)
+	}
+}
+
 func TestColorize(t *testing.T) {
 	cases := []struct {
 		attrs  []SgrAttr
@@ -114,6 +137,67 @@ func TestColorize(t *testing.T) {
 	}
 }
 
+func TestAppendColorize(t *testing.T) {
+	cases := []struct {
+		dst    string
+		attrs  []SgrAttr
+		expOut string
+	}{
+		{"", []SgrAttr{}, " Colorize me! "},
+		{"> ", []SgrAttr{}, ">  Colorize me! "},
+		{"", []SgrAttr{Bold, BlackFg, WhiteBbg}, "\033[1m\033[30m\033[107m Colorize me! \033[0m"},
+		{"> ", []SgrAttr{Rgb8(FG, 44)}, "> \033[38;5;44m Colorize me! \033[0m"},
+	}
+	for _, c := range cases {
+		t.Run(c.expOut, func(t *testing.T) {
+			out := AppendColorize([]byte(c.dst), " Colorize me! ", c.attrs...)
+			if string(out) != c.expOut {
+				t.Errorf("Have: %s, want: %s", out, c.expOut)
+			}
+			if col := Colorize(" Colorize me! ", c.attrs...); c.dst+col != c.expOut {
+				t.Errorf("Have: %s, want: %s", c.dst+col, c.expOut)
+			}
+		})
+	}
+}
+
+func TestAppendColorizeAllocs(t *testing.T) {
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = AppendColorize(buf[:0], "Colorize me!", Bold, BlackFg)
+	})
+	if allocs != 0 {
+		t.Errorf("Have: %.0f allocs, want: 0", allocs)
+	}
+}
+
+// errWriter fails every write with errWrite.
+type errWriter struct{}
+
+var errWrite = errors.New("Write error")
+
+func (errWriter) Write([]byte) (int, error) { return 0, errWrite }
+
+func TestWriteColorized(t *testing.T) {
+	var b strings.Builder
+	n, err := WriteColorized(&b, "Colorize me!", Bold, Rgb24(BG, 1, 2, 3))
+	exp := "\033[1m\033[48;2;1;2;3mColorize me!\033[0m"
+	if err != nil {
+		t.Fatalf("Have: %v, want: nil", err)
+	}
+	if b.String() != exp || n != len(exp) {
+		t.Errorf("Have: %q (%d), want: %q (%d)", b.String(), n, exp, len(exp))
+	}
+	long := strings.Repeat("x", 2*maxPooledBuf)
+	b.Reset()
+	if _, err := WriteColorized(&b, long); err != nil || b.String() != long {
+		t.Errorf("Have: %d bytes and %v, want: %d bytes and nil", b.Len(), err, len(long))
+	}
+	if _, err := WriteColorized(errWriter{}, "Colorize me!", Bold); err != errWrite {
+		t.Errorf("Have: %v, want: %v", err, errWrite)
+	}
+}
+
 func TestRgb8(t *testing.T) {
 	cases := []struct {
 		l      Layer
@@ -0,0 +1,142 @@
+package termcolstest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mdm-code/termcols"
+)
+
+// Span is a run of text displayed with the same attributes.
+type Span struct {
+	Text  string
+	State termcols.State
+}
+
+// Spans splits s into runs of text with the display attributes set by SGR
+// control sequences in effect for each one of them. Control sequences that
+// are not SGR sequences are kept in the text. Text with the same attributes
+// makes up a single span however it was colorized, so the spans of two
+// strings are the same when they look the same on the terminal.
+func Spans(s string) []Span {
+	var result []Span
+	for _, c := range cells(s) {
+		if n := len(result); n > 0 && result[n-1].State == c.state {
+			result[n-1].Text += c.text
+			continue
+		}
+		result = append(result, Span{c.text, c.state})
+	}
+	return result
+}
+
+// Diff compares have and want by their text and the display attributes of
+// each character, and it returns an empty string when they look the same on
+// the terminal. Otherwise, it describes the first difference along with the
+// readable forms of both strings.
+func Diff(have, want string) string {
+	hc, wc := cells(have), cells(want)
+	line, col := 1, 1
+	for i := 0; i < max(len(hc), len(wc)); i++ {
+		if i < len(hc) && i < len(wc) && hc[i] == wc[i] {
+			if hc[i].text == "\n" {
+				line, col = line+1, 1
+			} else {
+				col++
+			}
+			continue
+		}
+		return fmt.Sprintf(
+			"styled text differs at line %d, column %d: have %s; want %s\nhave: %s\nwant: %s",
+			line, col, describe(hc, i), describe(wc, i), Readable(have), Readable(want),
+		)
+	}
+	return ""
+}
+
+// AssertEqual marks the test as failed with the description returned by Diff
+// when have and want do not look the same on the terminal.
+func AssertEqual(t testing.TB, have, want string) {
+	t.Helper()
+	if d := Diff(have, want); d != "" {
+		t.Error(d)
+	}
+}
+
+// AssertStyle marks the test as failed unless the text substr occurs in s and
+// each one of its occurrences is displayed exactly with attrs applied to the
+// terminal defaults. Text is matched without control sequences, so substr may
+// span several differently colorized parts of s.
+func AssertStyle(t testing.TB, s, substr string, attrs ...termcols.SgrAttr) {
+	t.Helper()
+	want := termcols.NewState(attrs...)
+	cs := cells(s)
+	var plain strings.Builder
+	offsets := make([]int, 0, len(cs))
+	for _, c := range cs {
+		offsets = append(offsets, plain.Len())
+		plain.WriteString(c.text)
+	}
+	text := plain.String()
+	if substr == "" || !strings.Contains(text, substr) {
+		t.Errorf("%q not found in %s", substr, Readable(s))
+		return
+	}
+	for from := 0; ; {
+		i := strings.Index(text[from:], substr)
+		if i < 0 {
+			return
+		}
+		start, end := from+i, from+i+len(substr)
+		for j, c := range cs {
+			if offsets[j] >= start && offsets[j] < end && c.state != want {
+				t.Errorf("%q is %s; want %s", substr, formatState(c.state), formatState(want))
+				return
+			}
+		}
+		from = end
+	}
+}
+
+// Cell is a single character or a control sequence other than an SGR
+// sequence, along with the display attributes in effect for it.
+type cell struct {
+	text  string
+	state termcols.State
+}
+
+// Cells splits s into cells, applying its SGR control sequences on the way.
+func cells(s string) []cell {
+	var result []cell
+	var state termcols.State
+	for _, tok := range tokens(s) {
+		switch {
+		case tok.seq && strings.HasPrefix(tok.text, termcols.Csi) && strings.HasSuffix(tok.text, "m"):
+			state = state.Apply(termcols.SgrAttr(tok.text))
+		case tok.seq:
+			result = append(result, cell{tok.text, state})
+		default:
+			for _, r := range tok.text {
+				result = append(result, cell{string(r), state})
+			}
+		}
+	}
+	return result
+}
+
+// Describe describes the cell i of cs for a failure message.
+func describe(cs []cell, i int) string {
+	if i >= len(cs) {
+		return "end of text"
+	}
+	return fmt.Sprintf("%q %s", Readable(cs[i].text), formatState(cs[i].state))
+}
+
+// FormatState returns the readable tokens that set the state s.
+func formatState(s termcols.State) string {
+	if s == (termcols.State{}) {
+		return "<default>"
+	}
+	return Readable(string(termcols.Transition(termcols.State{}, s)))
+}
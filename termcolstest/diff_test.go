@@ -0,0 +1,128 @@
+package termcolstest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mdm-code/termcols"
+)
+
+func TestSpans(t *testing.T) {
+	red := termcols.NewState(termcols.RedFg)
+	cases := []struct {
+		name string
+		s    string
+		exp  []Span
+	}{
+		{"empty", "", nil},
+		{"plain", "text", []Span{{"text", termcols.State{}}}},
+		{
+			"colorize",
+			"a" + termcols.Colorize("bc", termcols.RedFg) + "d",
+			[]Span{{"a", termcols.State{}}, {"bc", red}, {"d", termcols.State{}}},
+		},
+		{
+			"redundant",
+			"\033[31ma\033[0m\033[31mb\033[1m\033[22mc",
+			[]Span{{"abc", red}},
+		},
+		{
+			"csi",
+			"\033[31ma\033[2Kb",
+			[]Span{{"a\033[2Kb", red}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if have := Spans(c.s); !reflect.DeepEqual(have, c.exp) {
+				t.Errorf("Have: %+v; want: %+v", have, c.exp)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		name string
+		have string
+		want string
+		exp  string
+	}{
+		{"equal", "text", "text", ""},
+		{"equivalent", "\033[1;31mERROR\033[0m", "\033[1m\033[31mERROR\033[m", ""},
+		{
+			"text",
+			"ab\ncd",
+			"ab\nce",
+			"styled text differs at line 2, column 2: have \"d\" <default>; want \"e\" <default>\nhave: ab\ncd\nwant: ab\nce",
+		},
+		{
+			"style",
+			termcols.Colorize("ok", termcols.GreenFg),
+			termcols.Colorize("ok", termcols.Bold, termcols.GreenFg),
+			"styled text differs at line 1, column 1: have \"o\" <fg:32>; want \"o\" <bold><fg:32>\nhave: <fg:32>ok<reset>\nwant: <bold><fg:32>ok<reset>",
+		},
+		{
+			"shorter",
+			"ab",
+			"abc",
+			"styled text differs at line 1, column 3: have end of text; want \"c\" <default>\nhave: ab\nwant: abc",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if have := Diff(c.have, c.want); have != c.exp {
+				t.Errorf("Have: %q; want: %q", have, c.exp)
+			}
+		})
+	}
+}
+
+func TestAssertEqual(t *testing.T) {
+	cases := []struct {
+		name string
+		have string
+		want string
+		exp  int
+	}{
+		{"equal", "\033[1;34mx", "\033[34m\033[1mx", 0},
+		{"differ", "\033[1mx", "x", 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fs := failures(func(t testing.TB) { AssertEqual(t, c.have, c.want) })
+			if len(fs) != c.exp {
+				t.Errorf("Have: %q; want %d failures", fs, c.exp)
+			}
+		})
+	}
+}
+
+func TestAssertStyle(t *testing.T) {
+	s := "[" + termcols.Colorize("ERROR", termcols.Bold, termcols.RedFg) + "] disk " +
+		termcols.Colorize("full", termcols.Underline)
+	cases := []struct {
+		name   string
+		s      string
+		substr string
+		attrs  []termcols.SgrAttr
+		exp    string
+	}{
+		{"match", s, "ERROR", []termcols.SgrAttr{termcols.RedFg, termcols.Bold}, ""},
+		{"plain", s, "] disk ", nil, ""},
+		{"every", s + " " + termcols.Colorize("ERROR", termcols.RedFg), "ERROR", []termcols.SgrAttr{termcols.Bold, termcols.RedFg}, `"ERROR" is <fg:31>; want <bold><fg:31>`},
+		{"partly", s, "disk full", nil, `"disk full" is <underline>; want <default>`},
+		{"style", s, "ERROR", []termcols.SgrAttr{termcols.RedFg}, `"ERROR" is <bold><fg:31>; want <fg:31>`},
+		{"missing", s, "WARN", nil, `"WARN" not found in [<bold><fg:31>ERROR<reset>] disk <underline>full<reset>`},
+		{"empty", s, "", nil, `"" not found in [<bold><fg:31>ERROR<reset>] disk <underline>full<reset>`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fs := failures(func(t testing.TB) { AssertStyle(t, c.s, c.substr, c.attrs...) })
+			if have := strings.Join(fs, "\n"); have != c.exp {
+				t.Errorf("Have: %q; want: %q", have, c.exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package termcolstest_test
+
+import (
+	"fmt"
+
+	"github.com/mdm-code/termcols"
+	"github.com/mdm-code/termcols/termcolstest"
+)
+
+// ExampleReadable shows how control sequences read in readable form.
+func ExampleReadable() {
+	s := termcols.Colorize("Hello", termcols.Bold, termcols.BlueFg, termcols.Rgb24(termcols.BG, 57, 124, 12))
+	fmt.Println(termcolstest.Readable(s))
+	// Output:
+	// <bold><fg:34><bg:#397c0c>Hello<reset>
+}
+
+// ExampleDiff shows how styled text is compared by what it looks like.
+func ExampleDiff() {
+	fmt.Printf("%q\n", termcolstest.Diff("\033[1;31mERROR\033[0m", "\033[1m\033[31mERROR\033[m"))
+	fmt.Println(termcolstest.Diff("\033[31mERROR\033[0m", "\033[1;31mERROR\033[0m"))
+	// Output:
+	// ""
+	// styled text differs at line 1, column 1: have "E" <fg:31>; want "E" <bold><fg:31>
+	// have: <fg:31>ERROR<reset>
+	// want: <bold><fg:31>ERROR<reset>
+}
@@ -0,0 +1,38 @@
+package termcolstest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// NOTE: The flag is registered with the default flag set when the package is
+// imported, so its name is prefixed with the name of the package to leave the
+// plain -update flag to the test packages importing it.
+var update = flag.Bool("termcolstest.update", false, "update golden files of styled output")
+
+// AssertGolden marks the test as failed unless the readable form of have
+// matches the golden file testdata/name.golden. With the -termcolstest.update
+// flag, the golden file is written instead.
+func AssertGolden(t testing.TB, have, name string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	readable := Readable(have)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(readable), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v: run the test with -termcolstest.update to create it", err)
+	}
+	if readable != string(want) {
+		t.Errorf("styled text differs from %s\nhave: %s\nwant: %s", path, readable, want)
+	}
+}
@@ -0,0 +1,77 @@
+package termcolstest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mdm-code/termcols"
+)
+
+const report = "\033[1;31mERROR\033[0m disk full\n\033[2m2 warnings\033[0m\n"
+
+func TestAssertGolden(t *testing.T) {
+	cases := []struct {
+		name string
+		have string
+		exp  int
+	}{
+		{"match", report, 0},
+		{"differ", strings.Replace(report, "1;31", "31", 1), 1},
+		{"missing", "", 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			golden := "report"
+			if c.name == "missing" {
+				golden = "missing"
+			}
+			fs := failures(func(t testing.TB) { AssertGolden(t, c.have, golden) })
+			if len(fs) != c.exp {
+				t.Errorf("Have: %q; want %d failures", fs, c.exp)
+			}
+		})
+	}
+}
+
+// Test that the flag leaves -update free for the packages importing this one.
+func TestUpdateFlag(t *testing.T) {
+	if flag.Lookup("update") != nil {
+		t.Error("Have: -update registered, want: none")
+	}
+	if flag.Lookup("termcolstest.update") == nil {
+		t.Error("Have: none, want: -termcolstest.update registered")
+	}
+}
+
+// Test that golden files are written with the -termcolstest.update flag.
+func TestAssertGoldenUpdate(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	defer func(v bool) { *update = v }(*update)
+
+	*update = true
+	have := termcols.Colorize("ok", termcols.GreenFg)
+	if fs := failures(func(t testing.TB) { AssertGolden(t, have, "new") }); len(fs) != 0 {
+		t.Fatalf("Have: %q; want no failures", fs)
+	}
+	b, err := os.ReadFile(filepath.Join("testdata", "new.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<fg:32>ok<reset>"; string(b) != want {
+		t.Errorf("Have: %q; want: %q", b, want)
+	}
+	*update = false
+	if fs := failures(func(t testing.TB) { AssertGolden(t, have, "new") }); len(fs) != 0 {
+		t.Errorf("Have: %q; want no failures", fs)
+	}
+}
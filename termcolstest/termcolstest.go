@@ -0,0 +1,147 @@
+/*
+Package termcolstest provides utilities for testing colored terminal output.
+
+Raw control sequences make expected values in tests hard to read and failures
+hard to make sense of. Readable turns control sequences into tokens such as
+<bold><fg:34>, and the assertions in this package compare text by what it
+looks like on the terminal rather than byte by byte, so "\033[1;31m" and
+"\033[1m\033[31m" count as the same. When they fail, they point at the first
+character that differs in text or in style.
+
+Golden files under testdata keep the readable form of the output, and they
+are rewritten when the tests are run with the -termcolstest.update flag.
+
+# Usage
+
+	package main
+
+	import (
+		"testing"
+
+		"github.com/mdm-code/termcols"
+		"github.com/mdm-code/termcols/termcolstest"
+	)
+
+	func TestReport(t *testing.T) {
+		out := report()
+		termcolstest.AssertStyle(t, out, "ERROR", termcols.Bold, termcols.RedFg)
+		termcolstest.AssertGolden(t, out, "report")
+	}
+*/
+package termcolstest
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mdm-code/termcols"
+)
+
+// Readable returns s with every control sequence replaced by a readable token
+// in angle brackets. SGR control sequences are split into one token per
+// attribute:
+//
+//   - display attributes read <bold>, <italic>, <no-bold> and so on, and the
+//     reset sequence reads <reset>;
+//   - the 16 colors read <fg:34> or <bg:101> after their SGR parameter, and
+//     default colors read <fg:default>;
+//   - 8-bit colors read <fg:rgb8(196)> and 24-bit colors <bg:#ff8700>.
+//
+// Parameters that are not recognized read <sgr:params>, other CSI control
+// sequences read <csi:params> followed by their final byte, OSC control
+// sequences read <osc:params> without their terminator, and lone escape
+// characters read <esc>.
+func Readable(s string) string {
+	var b strings.Builder
+	for _, tok := range tokens(s) {
+		if !tok.seq {
+			b.WriteString(tok.text)
+			continue
+		}
+		b.WriteString(readableSeq(tok.text))
+	}
+	return b.String()
+}
+
+// ReadableSeq returns the readable tokens of the single control sequence seq.
+func readableSeq(seq string) string {
+	if strings.HasPrefix(seq, termcols.Osc) {
+		return "<osc:" + oscParams(seq) + ">"
+	}
+	if !strings.HasPrefix(seq, termcols.Csi) {
+		return "<esc>"
+	}
+	parts, ok := termcols.SgrAttr(seq).Split()
+	if !ok {
+		return "<csi:" + seq[len(termcols.Csi):] + ">"
+	}
+	var b strings.Builder
+	for _, a := range parts {
+		b.WriteString("<" + readableAttr(a) + ">")
+	}
+	return b.String()
+}
+
+// ReadableAttr names the single SGR attribute a split off a control sequence.
+func readableAttr(a termcols.SgrAttr) string {
+	if name, ok := termcols.OffName(a); ok {
+		return name
+	}
+	params := string(a[len(termcols.Csi) : len(a)-1])
+	name, named := termcols.Name(a)
+	p, ok := a.Params()
+	if !ok {
+		return "sgr:" + params
+	}
+	switch {
+	case p[0] >= 30 && p[0] <= 37, p[0] >= 90 && p[0] <= 97:
+		return "fg:" + params
+	case p[0] >= 40 && p[0] <= 47, p[0] >= 100 && p[0] <= 107:
+		return "bg:" + params
+	case p[0] == 39:
+		return "fg:default"
+	case p[0] == 49:
+		return "bg:default"
+	case (p[0] == 38 || p[0] == 48) && named:
+		layer := "fg"
+		if p[0] == 48 {
+			layer = "bg"
+		}
+		if p[1] == 5 {
+			return layer + ":rgb8(" + strconv.Itoa(p[2]) + ")"
+		}
+		return layer + ":" + termcols.Color{R: uint8(p[2]), G: uint8(p[3]), B: uint8(p[4])}.Hex()
+	case p[0] >= 1 && p[0] <= 9 && named:
+		return name
+	}
+	return "sgr:" + params
+}
+
+// OscParams returns the parameters of the OSC control sequence seq without the
+// BEL or ST terminator.
+func oscParams(seq string) string {
+	s := strings.TrimPrefix(seq, termcols.Osc)
+	if t, ok := strings.CutSuffix(s, termcols.St); ok {
+		return t
+	}
+	return strings.TrimSuffix(s, "\a")
+}
+
+// Token is either a control sequence or a run of text.
+type token struct {
+	text string
+	seq  bool
+}
+
+// Tokens splits s into control sequences and runs of text with
+// termcols.ScanSequences.
+func tokens(s string) []token {
+	var result []token
+	data := []byte(s)
+	for len(data) > 0 {
+		n, tok, _ := termcols.ScanSequences(data, true)
+		result = append(result, token{string(tok), tok[0] == termcols.Esc[0]})
+		data = data[n:]
+	}
+	return result
+}
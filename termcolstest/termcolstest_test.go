@@ -0,0 +1,79 @@
+package termcolstest
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/mdm-code/termcols"
+)
+
+// FakeT records failures reported by assertions under test.
+type fakeT struct {
+	testing.TB
+	failures []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Error(args ...any) {
+	t.failures = append(t.failures, fmt.Sprint(args...))
+}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Fatal(args ...any) {
+	t.Error(args...)
+	runtime.Goexit()
+}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+	t.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// Failures runs the assertion f with a fakeT and returns the failures.
+func failures(f func(t testing.TB)) []string {
+	t := &fakeT{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f(t)
+	}()
+	<-done
+	return t.failures
+}
+
+func TestReadable(t *testing.T) {
+	cases := []struct {
+		name string
+		s    string
+		exp  string
+	}{
+		{"plain", "text", "text"},
+		{"empty", "", ""},
+		{"colorize", termcols.Colorize("text", termcols.Bold, termcols.BlueFg), "<bold><fg:34>text<reset>"},
+		{"combined", "\033[1;3;101mx\033[m", "<bold><italic><bg:101>x<reset>"},
+		{"switch-off", "\033[22;23;24;25;27;28;29;39;49m", "<no-bold><no-italic><no-underline><no-blink><no-reverse><no-hide><no-strike><fg:default><bg:default>"},
+		{"rgb8", string(termcols.Rgb8(termcols.FG, 196)) + "x", "<fg:rgb8(196)>x"},
+		{"rgb24", string(termcols.Rgb24(termcols.BG, 255, 135, 0)) + "x", "<bg:#ff8700>x"},
+		{"extended-and-more", "\033[38;5;1;4m", "<fg:rgb8(1)><underline>"},
+		{"bad-extended", "\033[38;5;300m", "<sgr:38;5;300>"},
+		{"short-extended", "\033[48;2;1m", "<sgr:48;2;1>"},
+		{"unknown-param", "\033[6m", "<sgr:6>"},
+		{"malformed", "\033[1:2m", "<sgr:1:2>"},
+		{"csi", "\033[2Kx", "<csi:2K>x"},
+		{"osc-bel", "\033]0;title\ax", "<osc:0;title>x"},
+		{"osc-st", "\033]8;;https://example.com\033\\link", "<osc:8;;https://example.com>link"},
+		{"esc", "\033(Bx", "<esc>(Bx"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if have := Readable(c.s); have != c.exp {
+				t.Errorf("Have: %q; want: %q", have, c.exp)
+			}
+		})
+	}
+}
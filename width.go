@@ -0,0 +1,255 @@
+package termcols
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WideRanges lists code points taking up two terminal cells: East Asian wide
+// and fullwidth characters along with the common emoji blocks.
+var wideRanges = [...][2]rune{
+	{0x1100, 0x115f},
+	{0x231a, 0x231b},
+	{0x2329, 0x232a},
+	{0x23e9, 0x23ec},
+	{0x25fd, 0x25fe},
+	{0x2614, 0x2615},
+	{0x2e80, 0x303e},
+	{0x3041, 0x33ff},
+	{0x3400, 0x4dbf},
+	{0x4e00, 0x9fff},
+	{0xa000, 0xa4cf},
+	{0xa960, 0xa97f},
+	{0xac00, 0xd7a3},
+	{0xf900, 0xfaff},
+	{0xfe10, 0xfe19},
+	{0xfe30, 0xfe6f},
+	{0xff00, 0xff60},
+	{0xffe0, 0xffe6},
+	{0x1f300, 0x1f64f},
+	{0x1f680, 0x1f6ff},
+	{0x1f900, 0x1f9ff},
+	{0x1fa70, 0x1faff},
+	{0x20000, 0x2fffd},
+	{0x30000, 0x3fffd},
+}
+
+// Width returns the number of terminal cells taken up by the string s. Escape
+// sequences, control characters and combining marks take up no cells, and
+// East Asian wide characters and emoji take up two.
+func Width(s string) int {
+	var n int
+	for len(s) > 0 {
+		tok, w, _ := nextToken(s)
+		n += w
+		s = s[len(tok):]
+	}
+	return n
+}
+
+// Truncate shortens the string s to at most width terminal cells, replacing
+// the cut off part with tail, such as "…". Escape sequences are never split,
+// and the ones following the cut are dropped. Should s be styled at the point
+// of the cut, the tail is written in the same style and followed by the
+// control sequence resetting it. The string s is returned as it is when it
+// fits in width.
+func Truncate(s string, width int, tail string) string {
+	if Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	tw := Width(tail)
+	if tw > width {
+		tail, tw = Truncate(tail, width, ""), width
+	}
+	var b strings.Builder
+	var state State
+	var n int
+	for len(s) > 0 {
+		tok, w, seq := nextToken(s)
+		if n+w > width-tw {
+			break
+		}
+		if seq {
+			state = state.Apply(SgrAttr(tok))
+		}
+		b.WriteString(tok)
+		n += w
+		s = s[len(tok):]
+	}
+	b.WriteString(tail)
+	b.WriteString(string(Transition(state, State{})))
+	return b.String()
+}
+
+// PadRight appends spaces to the string s until it takes up width terminal
+// cells.
+func PadRight(s string, width int) string {
+	if n := width - Width(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// PadLeft prepends spaces to the string s until it takes up width terminal
+// cells.
+func PadLeft(s string, width int) string {
+	if n := width - Width(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+// Center surrounds the string s with spaces until it takes up width terminal
+// cells. When the padding cannot be split evenly, the extra space goes to
+// the right.
+func Center(s string, width int) string {
+	if n := width - Width(s); n > 0 {
+		return strings.Repeat(" ", n/2) + s + strings.Repeat(" ", n-n/2)
+	}
+	return s
+}
+
+// Wrap breaks the string s into lines of at most width terminal cells. Lines
+// are broken at spaces, which are dropped at the ends of lines, and words
+// longer than width are broken wherever they reach it. Line breaks already in
+// s are kept along with the spaces indenting the lines following them, but
+// lines broken by Wrap are not indented. Escape sequences are never split,
+// and the style in effect at a break is reset at the end of the line and
+// opened again at the start of the next one, so that each line can be printed
+// on its own. The string s is returned as it is when width is not positive.
+//
+// Tabs are not expanded: like other control characters they take up no cells
+// and do not break lines, so they should be replaced with spaces beforehand.
+func Wrap(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	w := wrapper{width: width, indent: true}
+	for len(s) > 0 {
+		tok, n, seq := nextToken(s)
+		s = s[len(tok):]
+		switch {
+		case tok == "\n":
+			w.flush()
+			w.spaces = 0
+			w.newline()
+			w.indent = true
+		case tok == " ":
+			w.flush()
+			w.spaces++
+		default:
+			w.word = append(w.word, token{tok, n, seq})
+			w.wordWidth += n
+		}
+	}
+	w.flush()
+	return w.b.String()
+}
+
+// Token is a piece of text that cannot be split: an escape sequence or a
+// single character along with the combining marks following it.
+type token struct {
+	s     string
+	width int
+	seq   bool
+}
+
+// Wrapper holds the state of Wrap.
+type wrapper struct {
+	b         strings.Builder
+	width     int
+	state     State
+	line      int
+	indent    bool
+	spaces    int
+	word      []token
+	wordWidth int
+}
+
+// Flush writes out the pending word preceded by the pending spaces, breaking
+// the line first if it does not fit.
+func (w *wrapper) flush() {
+	if len(w.word) == 0 {
+		return
+	}
+	switch {
+	case w.line == 0 && w.indent:
+		// NOTE: Indentation wider than the line is cut down to leave room
+		// for at least one character.
+		n := min(w.spaces, w.width-1)
+		w.b.WriteString(strings.Repeat(" ", n))
+		w.line += n
+	case w.line == 0:
+	case w.line+w.spaces+w.wordWidth > w.width:
+		w.newline()
+	default:
+		w.b.WriteString(strings.Repeat(" ", w.spaces))
+		w.line += w.spaces
+	}
+	w.spaces, w.indent = 0, false
+	for _, t := range w.word {
+		if !t.seq && w.line > 0 && w.line+t.width > w.width {
+			w.newline()
+		}
+		if t.seq {
+			w.state = w.state.Apply(SgrAttr(t.s))
+		}
+		w.b.WriteString(t.s)
+		w.line += t.width
+	}
+	w.word, w.wordWidth = w.word[:0], 0
+}
+
+// Newline breaks the line, resetting the style in effect and opening it
+// again on the next line.
+func (w *wrapper) newline() {
+	w.b.WriteString(string(Transition(w.state, State{})))
+	w.b.WriteByte('\n')
+	w.b.WriteString(string(Transition(State{}, w.state)))
+	w.line = 0
+}
+
+// NextToken returns the token at the start of the string s along with its
+// width and whether it is an escape sequence. A character is returned along
+// with the zero-width characters following it.
+func nextToken(s string) (string, int, bool) {
+	if s[0] == Esc[0] {
+		return s[:seqLen(s, true)], 0, true
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	w := runeWidth(r)
+	if r == '\n' || r == ' ' {
+		return s[:n], w, false
+	}
+	for n < len(s) && s[n] != Esc[0] {
+		next, size := utf8.DecodeRuneInString(s[n:])
+		if runeWidth(next) != 0 || next == '\n' || next < 0x20 {
+			break
+		}
+		n += size
+	}
+	return s[:n], w, false
+}
+
+// RuneWidth returns the number of terminal cells taken up by the rune r.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20, r >= 0x7f && r < 0xa0:
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+	for _, rg := range wideRanges {
+		if r < rg[0] {
+			break
+		}
+		if r <= rg[1] {
+			return 2
+		}
+	}
+	return 1
+}
@@ -0,0 +1,135 @@
+package termcols
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	cases := []struct {
+		name string
+		s    string
+		exp  int
+	}{
+		{"empty", "", 0},
+		{"ascii", "Hello", 5},
+		{"colorized", Colorize("Hello", Bold, Rgb24(FG, 1, 2, 3)), 5},
+		{"wide", "日本語", 6},
+		{"emoji", "ok 👍", 5},
+		{"combining", "e\u0301te\u0301", 3},
+		{"hyperlink", Osc + "8;;https://example.com" + St + "link" + Osc + "8;;\a", 4},
+		{"control", "a\tb\x00", 2},
+		{"lone-esc", "a" + Esc, 1},
+		{"invalid", "a\xffb", 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if out := Width(c.s); out != c.exp {
+				t.Errorf("Have: %d, want: %d", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	red := string(RedFg)
+	cases := []struct {
+		name  string
+		s     string
+		width int
+		tail  string
+		exp   string
+	}{
+		{"fits", "Hello", 5, "…", "Hello"},
+		{"plain", "Hello, world", 8, "…", "Hello, …"},
+		{"no-tail", "Hello, world", 5, "", "Hello"},
+		{"zero", "Hello", 0, "…", ""},
+		{"wide-tail", "Hello", 2, "...", ".."},
+		{"styled", red + "Hello, world" + string(Reset), 6, "…", red + "Hello…" + string(Reset)},
+		{"closed", red + "Hi" + string(Reset) + " there", 4, "…", red + "Hi" + string(Reset) + " …"},
+		{"wide", "日本語です", 5, "…", "日本…"},
+		{"combining", "e\u0301e\u0301e", 2, "", "e\u0301e\u0301"},
+		{"seq-at-cut", "ab" + red + "cd", 2, "", "ab" + red + string(Reset)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := Truncate(c.s, c.width, c.tail)
+			if out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+			if w := Width(out); w > c.width {
+				t.Errorf("Have: %d, want at most: %d", w, c.width)
+			}
+		})
+	}
+}
+
+func TestPad(t *testing.T) {
+	s := Colorize("ab", Bold)
+	cases := []struct {
+		name string
+		fn   func(string, int) string
+		s    string
+		w    int
+		exp  string
+	}{
+		{"right", PadRight, s, 5, s + "   "},
+		{"left", PadLeft, s, 5, "   " + s},
+		{"center", Center, s, 5, " " + s + "  "},
+		{"center-even", Center, s, 6, "  " + s + "  "},
+		{"wide", PadRight, "日本", 5, "日本 "},
+		{"right-wider", PadRight, "Hello", 3, "Hello"},
+		{"left-wider", PadLeft, "Hello", 3, "Hello"},
+		{"center-wider", Center, "Hello", 3, "Hello"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if out := c.fn(c.s, c.w); out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	red, reset := string(RedFg), string(Reset)
+	cases := []struct {
+		name  string
+		s     string
+		width int
+		exp   string
+	}{
+		{"fits", "Hello world", 11, "Hello world"},
+		{"words", "The quick brown fox", 10, "The quick\nbrown fox"},
+		{"long-word", "abcdefgh ij", 3, "abc\ndef\ngh\nij"},
+		{"spaces", "a  b   c", 4, "a  b\nc"},
+		{"trailing", "ab  ", 5, "ab"},
+		{"newlines", "ab\n\ncd ef", 2, "ab\n\ncd\nef"},
+		{"wide", "日本語 です", 4, "日本\n語\nです"},
+		{"styled", red + "one two" + string(Reset), 3, red + "one" + reset + "\n" + red + "two" + string(Reset)},
+		{"styled-newline", red + "a\nb", 5, red + "a" + reset + "\n" + red + "b"},
+		{"unstyled", red + "a" + string(Reset) + " b", 1, red + "a" + string(Reset) + "\nb"},
+		{"indent", "  one two three", 9, "  one two\nthree"},
+		{"indent-lines", "a\n    b c\n  d", 6, "a\n    b\nc\n  d"},
+		{"indent-wide", "     ab", 3, "  a\nb"},
+		{"indent-blank", "a\n   \nb", 3, "a\n\nb"},
+		{"tab", "a\tb c", 3, "a\tb\nc"},
+		{"zero", "a b", 0, "a b"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := Wrap(c.s, c.width)
+			if out != c.exp {
+				t.Errorf("Have: %q, want: %q", out, c.exp)
+			}
+			if c.width <= 0 {
+				return
+			}
+			for _, l := range strings.Split(out, "\n") {
+				if w := Width(l); w > c.width {
+					t.Errorf("Have: %d, want at most: %d", w, c.width)
+				}
+			}
+		})
+	}
+}